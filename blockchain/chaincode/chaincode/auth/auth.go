@@ -0,0 +1,72 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package auth inspects the caller's Fabric identity so contract methods
+// can enforce role- and attribute-based access control with a two-line
+// guard instead of trusting every caller-supplied argument.
+package auth
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// ManufacturerMSP is the MSP ID a caller must belong to in order to act on
+// behalf of a drug manufacturer.
+const ManufacturerMSP = "ManufacturerMSP"
+
+// HospitalMSP is the MSP ID a caller must belong to in order to act as a
+// doctor. role/affiliation are X.509 attributes a caller's own org CA
+// issues, so checking them alone lets any other org on the channel
+// self-issue a cert claiming role=doctor and impersonate one; every
+// doctor guard must pair RequireRole/RequireAffiliation with
+// RequireMSP(ctx, HospitalMSP), mirroring the manufacturer side.
+const HospitalMSP = "HospitalMSP"
+
+// PatientMSP is the MSP ID of a patient's home org, the org whose members
+// the patientPrivate collection in collections_config.json admits.
+const PatientMSP = "PatientMSP"
+
+// RequireRole fails unless the caller's X.509 certificate carries a
+// "role" attribute equal to role, e.g. RequireRole(ctx, "doctor").
+func RequireRole(ctx contractapi.TransactionContextInterface, role string) error {
+	if err := ctx.GetClientIdentity().AssertAttributeValue("role", role); err != nil {
+		return fmt.Errorf("caller does not have role %q: %v", role, err)
+	}
+	return nil
+}
+
+// RequireAffiliation fails unless the caller's X.509 certificate carries
+// an "affiliation" attribute equal to hospitalName, e.g. a doctor acting
+// on behalf of the hospital that employs them.
+func RequireAffiliation(ctx contractapi.TransactionContextInterface, hospitalName string) error {
+	if err := ctx.GetClientIdentity().AssertAttributeValue("affiliation", hospitalName); err != nil {
+		return fmt.Errorf("caller is not affiliated with %q: %v", hospitalName, err)
+	}
+	return nil
+}
+
+// RequireMSP fails unless the caller's identity belongs to mspID.
+func RequireMSP(ctx contractapi.TransactionContextInterface, mspID string) error {
+	callerMSP, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return fmt.Errorf("failed to read caller's MSP ID: %v", err)
+	}
+	if callerMSP != mspID {
+		return fmt.Errorf("caller's MSP %q is not authorized, expected %q", callerMSP, mspID)
+	}
+	return nil
+}
+
+// CallerCommonName returns the CN of the caller's X.509 certificate, so a
+// method can recognize a patient identified by their own certificate
+// rather than by an affiliation attribute.
+func CallerCommonName(ctx contractapi.TransactionContextInterface) (string, error) {
+	cert, err := ctx.GetClientIdentity().GetX509Certificate()
+	if err != nil {
+		return "", fmt.Errorf("failed to read caller's certificate: %v", err)
+	}
+	return cert.Subject.CommonName, nil
+}
@@ -0,0 +1,65 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package auth_test
+
+import (
+	"testing"
+
+	"chaincode/chaincode/auth"
+	"chaincode/chaincode/mocks"
+)
+
+func TestRequireRole(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity(auth.ManufacturerMSP, map[string]string{"role": "doctor"}))
+
+	if err := auth.RequireRole(ctx, "doctor"); err != nil {
+		t.Fatalf("expected doctor role to be accepted, got error: %v", err)
+	}
+
+	if err := auth.RequireRole(ctx, "manufacturer"); err == nil {
+		t.Fatalf("expected manufacturer role check to fail for a doctor identity")
+	}
+}
+
+func TestRequireAffiliation(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity("HospitalMSP", map[string]string{
+		"role":        "doctor",
+		"affiliation": "general",
+	}))
+
+	if err := auth.RequireAffiliation(ctx, "general"); err != nil {
+		t.Fatalf("expected doctor affiliated with general to be accepted, got error: %v", err)
+	}
+
+	if err := auth.RequireAffiliation(ctx, "st-mary"); err == nil {
+		t.Fatalf("expected cross-hospital affiliation check to be denied")
+	}
+}
+
+func TestRequireMSPDeniesCrossOrgCaller(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity("HospitalMSP", map[string]string{"role": "manufacturer"}))
+
+	if err := auth.RequireMSP(ctx, auth.ManufacturerMSP); err == nil {
+		t.Fatalf("expected caller from HospitalMSP to be denied a ManufacturerMSP-only action")
+	}
+}
+
+func TestCallerCommonName(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	identity := mocks.NewClientIdentity("PatientMSP", nil)
+	identity.CommonName = "alice"
+	ctx.SetClientIdentity(identity)
+
+	cn, err := auth.CallerCommonName(ctx)
+	if err != nil {
+		t.Fatalf("CallerCommonName returned error: %v", err)
+	}
+	if cn != "alice" {
+		t.Fatalf("expected CN %q, got %q", "alice", cn)
+	}
+}
@@ -1,27 +1,25 @@
 package chaincode
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
-	"sync"
+	"strconv"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"chaincode/chaincode/auth"
 )
 
 type HospitalContract struct {
 	contractapi.Contract
 }
 
-var hospitals = map[string]*Hospital{}
-
 type Hospital struct {
-	Name      string
-	Contact   string
-	Reports   map[int]MedicalReport
-	Inventory map[string]HospitalDrug
-	Patients  map[string]string
-	Channels  map[string]bool
-	mu        sync.Mutex
+	Name    string
+	Contact string
 }
 
 type HospitalDrug struct {
@@ -30,13 +28,71 @@ type HospitalDrug struct {
 	HospitalName string
 }
 
+// hospitalReportsCollection holds every medical report's symptoms,
+// encrypted (see encryptSymptoms/decryptSymptoms below). Its collection
+// policy (see collections_config.json) can only scope membership to
+// HospitalMSP as a whole, not to one treating hospital: many hospitals
+// are attribute-differentiated identities within that single org, and
+// Fabric has no finer-grained collection membership than org. So every
+// HospitalMSP peer ends up holding every hospital's ciphertext; the
+// per-hospital secret passed via the transient map (never written to
+// the ledger, like traceCodeSecret in trace.go) is what actually keeps
+// one hospital's symptoms unreadable to another.
+const hospitalReportsCollection = "hospitalReports"
+
+// hospitalReportSecretTransientKey is the key a caller writing or
+// reading a report's symptoms passes the treating hospital's symmetric
+// encryption key under, via the transient map.
+const hospitalReportSecretTransientKey = "reportSecret"
+
+// MedicalReport is the record written to and read back from public world
+// state. It never carries Symptoms: ModifyReport writes that to
+// hospitalReportsCollection instead, and ViewReport returns it as part of
+// a MedicalReportView, not this struct.
 type MedicalReport struct {
+	ID          int
+	PatientName string
+	NeededDrugs []string
+}
+
+// MedicalReportPHI is the sensitive half of a medical report, written
+// only to hospitalReportsCollection and never to public world state.
+// EncryptedSymptoms is AES-256-GCM ciphertext, not plaintext: every
+// HospitalMSP peer replicates this collection, so the ciphertext alone
+// must not reveal another hospital's symptoms to a treating hospital
+// that hasn't been given the right secret.
+type MedicalReportPHI struct {
+	EncryptedSymptoms []byte
+}
+
+// MedicalReportView is what ViewReport returns: a MedicalReport merged
+// with the Symptoms held in hospitalReportsCollection. contractapi
+// serializes a transaction's return value directly as the chaincode
+// invoke response, so Symptoms must be an ordinary field here rather
+// than json:"-" on MedicalReport, or it would never reach a real caller.
+type MedicalReportView struct {
 	ID          int
 	PatientName string
 	Symptoms    string
 	NeededDrugs []string
 }
 
+func hospitalKey(ctx contractapi.TransactionContextInterface, name string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(hospitalKeyType, []string{name})
+}
+
+func hospitalPatientKey(ctx contractapi.TransactionContextInterface, hospitalName, patientName string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(hospitalPatientKeyType, []string{hospitalName, patientName})
+}
+
+func hospitalReportKey(ctx contractapi.TransactionContextInterface, hospitalName string, reportID int) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(hospitalReportKeyType, []string{hospitalName, strconv.Itoa(reportID)})
+}
+
+func hospitalDrugKey(ctx contractapi.TransactionContextInterface, hospitalName, traceCode string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(hospitalDrugKeyType, []string{hospitalName, traceCode})
+}
+
 // CreateHospital creates a new hospital record in the ledger.
 // Parameters:
 // - ctx: the transaction context provided by Hyperledger Fabric.
@@ -45,53 +101,66 @@ type MedicalReport struct {
 //
 // This function first checks if a hospital with the given name already exists.
 // If it does, it returns an error indicating the hospital already exists.
-// If not, it creates a new Hospital struct, initializes its fields, and stores it
-// in the hospitals map. It then serializes the hospital struct to JSON and stores
-// it in the ledger using PutState.
+// If not, it creates a new Hospital struct and stores it in the world state
+// under its composite key.
 //
 // Returns:
 //   - error: nil if the operation is successful, or an error message if it fails or
 //     the hospital already exists.
 func (hc *HospitalContract) CreateHospital(ctx contractapi.TransactionContextInterface, name, contact string) error {
-	if _, exists := hospitals[name]; exists {
+	key, err := hospitalKey(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	existing, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return fmt.Errorf("failed to read hospital from world state: %v", err)
+	}
+	if existing != nil {
 		return fmt.Errorf("hospital already exists")
 	}
 
 	hospital := &Hospital{
-		Name:      name,
-		Contact:   contact,
-		Reports:   make(map[int]MedicalReport),
-		Inventory: make(map[string]HospitalDrug),
-		Patients:  make(map[string]string),
-		Channels:  make(map[string]bool),
+		Name:    name,
+		Contact: contact,
 	}
 
-	hospitals[name] = hospital
 	hospitalJSON, err := json.Marshal(hospital)
 	if err != nil {
 		return err
 	}
 
-	return ctx.GetStub().PutState(name, hospitalJSON)
+	return ctx.GetStub().PutState(key, hospitalJSON)
 }
 
-// CreatePatientRecord creates a new patient record in the hospital's Patients map.
+// CreatePatientRecord admits a patient to a hospital by recording a
+// hospitalpatient composite key entry for the pair.
 func (hc *HospitalContract) CreatePatientRecord(ctx contractapi.TransactionContextInterface, hospitalName string, patientName string) error {
-	hospital, hospitalExists := hospitals[hospitalName]
-	if !hospitalExists {
-		return fmt.Errorf("hospital not found")
+	if err := auth.RequireRole(ctx, "doctor"); err != nil {
+		return err
+	}
+	if err := auth.RequireAffiliation(ctx, hospitalName); err != nil {
+		return err
+	}
+	if err := auth.RequireMSP(ctx, auth.HospitalMSP); err != nil {
+		return err
 	}
 
-	hospital.mu.Lock()
-	defer hospital.mu.Unlock()
+	valid, err := hc.ValidHospital(ctx, hospitalName)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("hospital not found")
+	}
 
-	hospital.Patients[patientName] = patientName
-	hospitalJSON, err := json.Marshal(hospital)
+	key, err := hospitalPatientKey(ctx, hospitalName, patientName)
 	if err != nil {
 		return err
 	}
 
-	return ctx.GetStub().PutState(hospitalName, hospitalJSON)
+	return ctx.GetStub().PutState(key, []byte(patientName))
 }
 
 // ModifyReport modifies a medical report for a patient in a hospital.
@@ -99,45 +168,116 @@ func (hc *HospitalContract) CreatePatientRecord(ctx contractapi.TransactionConte
 // - ctx: the transaction context provided by Hyperledger Fabric.
 // - hospitalName: the name of the hospital where the report is being created.
 // - patientName: the name of the patient for whom the report is being created.
-// - symptoms: the symptoms reported by the patient.
 // - neededDrugs: a list of drugs needed by the patient.
 //
-// This function first checks if the hospital exists. If not, it returns an error.
-// Then it checks if the patient exists in the hospital's records. If not, it returns an error.
-// If both exist, it creates a new medical report, assigns it an ID, and stores it in the hospital's reports map.
-// The function then serializes the hospital struct to JSON and updates the hospital record in the ledger.
+// symptoms is not a plain parameter: it arrives via
+// ctx.GetStub().GetTransient(), so it never appears in the public
+// proposal, and is written to hospitalReportsCollection rather than
+// world state.
+//
+// This function first checks if the patient is recorded at the hospital. If not,
+// it returns an error. It then creates a new medical report, assigns it the next
+// free ID, and stores it in the world state under its own composite key.
 //
 // Returns:
 // - int: the ID of the created report if the operation is successful.
 // - error: nil if the operation is successful, or an error message if it fails.
-func (hc *HospitalContract) ModifyReport(ctx contractapi.TransactionContextInterface, hospitalName, patientName, symptoms string, neededDrugs []string) (int, error) {
-	hospital, hospitalExists := hospitals[hospitalName]
-	if !hospitalExists {
-		return 0, fmt.Errorf("hospital not found")
+func (hc *HospitalContract) ModifyReport(ctx contractapi.TransactionContextInterface, hospitalName, patientName string, neededDrugs []string) (int, error) {
+	if err := auth.RequireRole(ctx, "doctor"); err != nil {
+		return 0, err
+	}
+	if err := auth.RequireAffiliation(ctx, hospitalName); err != nil {
+		return 0, err
+	}
+	if err := auth.RequireMSP(ctx, auth.HospitalMSP); err != nil {
+		return 0, err
 	}
 
-	hospital.mu.Lock()
-	defer hospital.mu.Unlock()
+	patientKey, err := hospitalPatientKey(ctx, hospitalName, patientName)
+	if err != nil {
+		return 0, err
+	}
 
-	if _, exists := hospital.Patients[patientName]; !exists {
+	patientJSON, err := ctx.GetStub().GetState(patientKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read hospital patient from world state: %v", err)
+	}
+	if patientJSON == nil {
 		return 0, fmt.Errorf("patient not found in hospital's list")
 	}
 
-	reportID := len(hospital.Reports) + 1
+	transient, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read transient data: %v", err)
+	}
+	symptoms := string(transient["symptoms"])
+
+	secret, err := hospitalReportSecret(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	existingReports, err := hc.GetReports(ctx, hospitalName)
+	if err != nil {
+		return 0, err
+	}
+	reportID := len(existingReports) + 1
+
 	report := MedicalReport{
 		ID:          reportID,
 		PatientName: patientName,
-		Symptoms:    symptoms,
 		NeededDrugs: neededDrugs,
 	}
 
-	hospital.Reports[reportID] = report
-	hospitalJSON, err := json.Marshal(hospital)
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return 0, err
+	}
+
+	key, err := hospitalReportKey(ctx, hospitalName, reportID)
+	if err != nil {
+		return 0, err
+	}
+	if err := ctx.GetStub().PutState(key, reportJSON); err != nil {
+		return 0, err
+	}
+
+	encryptedSymptoms, err := encryptSymptoms(ctx, secret, symptoms)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt symptoms: %v", err)
+	}
+
+	phiJSON, err := json.Marshal(MedicalReportPHI{EncryptedSymptoms: encryptedSymptoms})
 	if err != nil {
 		return 0, err
 	}
 
-	return reportID, ctx.GetStub().PutState(hospitalName, hospitalJSON)
+	return reportID, ctx.GetStub().PutPrivateData(hospitalReportsCollection, key, phiJSON)
+}
+
+// GetReports returns every medical report recorded by a hospital.
+func (hc *HospitalContract) GetReports(ctx contractapi.TransactionContextInterface, hospitalName string) ([]*MedicalReport, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(hospitalReportKeyType, []string{hospitalName})
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
+	var reports []*MedicalReport
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var report MedicalReport
+		if err := json.Unmarshal(kv.Value, &report); err != nil {
+			return nil, err
+		}
+		reports = append(reports, &report)
+	}
+
+	return reports, nil
 }
 
 // GetPatients returns a list of patients in a hospital.
@@ -145,45 +285,84 @@ func (hc *HospitalContract) ModifyReport(ctx contractapi.TransactionContextInter
 // - ctx: the transaction context provided by Hyperledger Fabric.
 // - hospitalName: the name of the hospital.
 //
-// This function first checks if the hospital exists. If not, it returns an error.
-// If the hospital exists, it retrieves the list of patients from the hospital's
-// Patients map and returns it.
+// This function walks the hospitalpatient composite key range for
+// hospitalName and returns every patient name found.
 //
 // Returns:
 // - []string: a list of patient names if the operation is successful.
 // - error: nil if the operation is successful, or an error message if it fails.
 func (hc *HospitalContract) GetPatients(ctx contractapi.TransactionContextInterface, hospitalName string) ([]string, error) {
-	hospital, hospitalExists := hospitals[hospitalName]
-	if !hospitalExists {
+	valid, err := hc.ValidHospital(ctx, hospitalName)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
 		return nil, fmt.Errorf("hospital not found")
 	}
 
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(hospitalPatientKeyType, []string{hospitalName})
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
 	var patientList []string
-	for patientName := range hospital.Patients {
-		patientList = append(patientList, patientName)
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		patientList = append(patientList, string(kv.Value))
 	}
 
 	return patientList, nil
 }
 
-// // GetHospitals retrieves a list of all hospitals.
-// // Parameters:
-// // - ctx: the transaction context provided by Hyperledger Fabric.
-// //
-// // This function iterates through the hospitals map and appends each hospital's name to a slice.
-// // It returns the list of hospital names.
-// //
-// // Returns:
-// // - []string: a slice containing the names of all hospitals if the operation is successful.
-// // - error: nil if the operation is successful, or an error message if it fails.
+// GetHospitals retrieves a list of all hospitals.
+// Parameters:
+// - ctx: the transaction context provided by Hyperledger Fabric.
+//
+// This function walks the hospital composite key range and appends each
+// hospital's name to a slice.
+//
+// Returns:
+// - []string: a slice containing the names of all hospitals if the operation is successful.
+// - error: nil if the operation is successful, or an error message if it fails.
 func (hc *HospitalContract) GetHospitals(ctx contractapi.TransactionContextInterface) ([]string, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(hospitalKeyType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
 	var hospitalList []string
-	for hospitalName := range hospitals {
-		hospitalList = append(hospitalList, hospitalName)
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		hospitalList = append(hospitalList, attributes[0])
 	}
+
 	return hospitalList, nil
 }
 
+// QueryHospitalDrugsByField runs a CouchDB Mongo-style rich query selector
+// against hospital inventory documents, so a caller can e.g. find every
+// drug dispensed by a given manufacturer without knowing its trace code.
+func (hc *HospitalContract) QueryHospitalDrugsByField(ctx contractapi.TransactionContextInterface, selector string) ([]*HospitalDrug, error) {
+	drugs := []*HospitalDrug{}
+	if err := queryByField(ctx, selector, &drugs); err != nil {
+		return nil, err
+	}
+	return drugs, nil
+}
+
 // AddDrugToHospitalInventory adds a drug to the hospital's inventory.
 // Parameters:
 // - ctx: the transaction context provided by Hyperledger Fabric.
@@ -192,28 +371,44 @@ func (hc *HospitalContract) GetHospitals(ctx contractapi.TransactionContextInter
 // - traceCode: the trace code of the drug.
 //
 // This function checks if the hospital exists. If not, it returns an error.
-// It then adds the drug to the hospital's inventory.
+// It then adds the drug to the hospital's inventory under its own
+// composite key.
 func (hc *HospitalContract) AddDrugToHospitalInventory(ctx contractapi.TransactionContextInterface, hospitalName, drugName, traceCode string) error {
-	hospital, hospitalExists := hospitals[hospitalName]
-	if !hospitalExists {
-		return fmt.Errorf("hospital not found")
+	if err := auth.RequireRole(ctx, "doctor"); err != nil {
+		return err
+	}
+	if err := auth.RequireAffiliation(ctx, hospitalName); err != nil {
+		return err
+	}
+	if err := auth.RequireMSP(ctx, auth.HospitalMSP); err != nil {
+		return err
 	}
 
-	hospital.mu.Lock()
-	defer hospital.mu.Unlock()
+	valid, err := hc.ValidHospital(ctx, hospitalName)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("hospital not found")
+	}
 
-	hospital.Inventory[traceCode] = HospitalDrug{
+	drug := HospitalDrug{
 		Name:         drugName,
 		TraceCode:    traceCode,
 		HospitalName: hospitalName,
 	}
 
-	hospitalJSON, err := json.Marshal(hospital)
+	drugJSON, err := json.Marshal(drug)
 	if err != nil {
 		return err
 	}
 
-	return ctx.GetStub().PutState(hospitalName, hospitalJSON)
+	key, err := hospitalDrugKey(ctx, hospitalName, traceCode)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(key, drugJSON)
 }
 
 // RemoveDrugFromHospitalInventory removes a drug from the hospital's inventory and returns its trace code.
@@ -222,44 +417,78 @@ func (hc *HospitalContract) AddDrugToHospitalInventory(ctx contractapi.Transacti
 // - hospitalName: the name of the hospital selling the drug.
 // - drugName: the name of the drug being sold.
 //
-// This function checks if the hospital exists. If not, it returns an error.
-// If the drug is available in the hospital's inventory, it is removed and its trace code is returned.
+// This function walks the hospital's drug composite key range. If the drug is
+// available in the hospital's inventory, it is removed and its trace code is
+// returned.
 //
 // Returns:
 // - string: the trace code if the operation is successful.
 // - error: nil if the operation is successful, or an error message if it fails.
 func (hc *HospitalContract) RemoveDrugFromHospitalInventory(ctx contractapi.TransactionContextInterface, hospitalName, drugName string) (string, error) {
-	hospital, hospitalExists := hospitals[hospitalName]
-	if !hospitalExists {
-		return "", fmt.Errorf("hospital not found")
+	if err := auth.RequireRole(ctx, "doctor"); err != nil {
+		return "", err
+	}
+	if err := auth.RequireAffiliation(ctx, hospitalName); err != nil {
+		return "", err
+	}
+	if err := auth.RequireMSP(ctx, auth.HospitalMSP); err != nil {
+		return "", err
 	}
 
-	hospital.mu.Lock()
-	defer hospital.mu.Unlock()
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(hospitalDrugKeyType, []string{hospitalName})
+	if err != nil {
+		return "", err
+	}
+	defer iterator.Close()
 
-	for traceCode, drug := range hospital.Inventory {
-		if drug.Name == drugName {
-			delete(hospital.Inventory, traceCode)
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return "", err
+		}
 
-			hospitalJSON, err := json.Marshal(hospital)
-			if err != nil {
-				return "", err
-			}
+		var drug HospitalDrug
+		if err := json.Unmarshal(kv.Value, &drug); err != nil {
+			return "", err
+		}
 
-			if err := ctx.GetStub().PutState(hospitalName, hospitalJSON); err != nil {
+		if drug.Name == drugName {
+			if err := ctx.GetStub().DelState(kv.Key); err != nil {
 				return "", err
 			}
-
-			return traceCode, nil // 返回溯源码
+			return drug.TraceCode, nil // 返回溯源码
 		}
 	}
 
 	return "", fmt.Errorf("drug not available")
 }
 
+// VerifyTraceCode decodes and verifies a drug's trace code, so a hospital
+// receiving a shipment or a patient being handed a drug can prove its
+// provenance without trusting the trace code's caller-supplied fields.
+// A legacy, unsigned trace code decodes without error but with
+// Verified false; since this method exists specifically to rule out
+// forged/unsigned codes, it rejects those outright rather than handing
+// back an unverified result.
+func (hc *HospitalContract) VerifyTraceCode(ctx contractapi.TransactionContextInterface, traceCode string) (*TraceCode, error) {
+	decoded, err := DecodeTraceCode(ctx, traceCode)
+	if err != nil {
+		return nil, err
+	}
+	if !decoded.Verified {
+		return nil, fmt.Errorf("trace code is not signed and cannot be verified")
+	}
+	return decoded, nil
+}
+
 // check hospital is valid or not
 func (hc *HospitalContract) ValidHospital(ctx contractapi.TransactionContextInterface, hospitalName string) (bool, error) {
-	hospitalJSON, err := ctx.GetStub().GetState(hospitalName)
+	key, err := hospitalKey(ctx, hospitalName)
+	if err != nil {
+		return false, err
+	}
+
+	hospitalJSON, err := ctx.GetStub().GetState(key)
 	if err != nil {
 		return false, fmt.Errorf("failed to read hospital from world state: %v", err)
 	}
@@ -277,21 +506,137 @@ func (hc *HospitalContract) ValidHospital(ctx contractapi.TransactionContextInte
 // - hospitalName: the name of the hospital.
 // - reportID: the ID of the medical report to retrieve.
 //
-// This function checks if the hospital exists and if the medical report exists and belongs to the specified patient.
-// If either condition is not met, it returns an error. Otherwise, it returns the medical report.
+// The caller must either be a doctor affiliated with hospitalName, or be
+// the patient themselves, identified by the CN on their own certificate.
+// This function then reads the report from its composite key and checks
+// that it belongs to the specified patient. If any condition is not met,
+// it returns an error. Otherwise, it merges in the Symptoms held in
+// hospitalReportsCollection and returns the medical report.
 //
 // Returns:
-// - MedicalReport: the medical report if found.
+// - MedicalReportView: the medical report, with Symptoms merged in, if found.
 // - error: nil if the operation is successful, or an error message if the hospital or report does not exist.
-func (hc *HospitalContract) ViewReport(ctx contractapi.TransactionContextInterface, patientName, hospitalName string, reportID int) (MedicalReport, error) {
-	hospital := hospitals[hospitalName]
-	if hospital == nil {
-		return MedicalReport{}, fmt.Errorf("hospital not found")
+func (hc *HospitalContract) ViewReport(ctx contractapi.TransactionContextInterface, patientName, hospitalName string, reportID int) (MedicalReportView, error) {
+	isDoctorAtHospital := auth.RequireRole(ctx, "doctor") == nil && auth.RequireAffiliation(ctx, hospitalName) == nil && auth.RequireMSP(ctx, auth.HospitalMSP) == nil
+	if !isDoctorAtHospital {
+		callerCN, err := auth.CallerCommonName(ctx)
+		if err != nil {
+			return MedicalReportView{}, err
+		}
+		if callerCN != patientName {
+			return MedicalReportView{}, fmt.Errorf("caller is neither a doctor at %q nor patient %q", hospitalName, patientName)
+		}
+	}
+
+	key, err := hospitalReportKey(ctx, hospitalName, reportID)
+	if err != nil {
+		return MedicalReportView{}, err
+	}
+
+	reportJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return MedicalReportView{}, fmt.Errorf("failed to read report from world state: %v", err)
+	}
+	if reportJSON == nil {
+		return MedicalReportView{}, fmt.Errorf("report not found")
+	}
+
+	var report MedicalReport
+	if err := json.Unmarshal(reportJSON, &report); err != nil {
+		return MedicalReportView{}, err
 	}
 
-	if report, exists := hospital.Reports[reportID]; exists && report.PatientName == patientName {
-		return report, nil
+	if report.PatientName != patientName {
+		return MedicalReportView{}, fmt.Errorf("report not found")
+	}
+
+	view := MedicalReportView{
+		ID:          report.ID,
+		PatientName: report.PatientName,
+		NeededDrugs: report.NeededDrugs,
+	}
+
+	phiJSON, err := ctx.GetStub().GetPrivateData(hospitalReportsCollection, key)
+	if err != nil {
+		return MedicalReportView{}, fmt.Errorf("failed to read report PHI from private data: %v", err)
+	}
+	if phiJSON != nil {
+		var phi MedicalReportPHI
+		if err := json.Unmarshal(phiJSON, &phi); err != nil {
+			return MedicalReportView{}, err
+		}
+
+		secret, err := hospitalReportSecret(ctx)
+		if err != nil {
+			return MedicalReportView{}, err
+		}
+
+		symptoms, err := decryptSymptoms(secret, phi.EncryptedSymptoms)
+		if err != nil {
+			return MedicalReportView{}, fmt.Errorf("failed to decrypt symptoms: %v", err)
+		}
+		view.Symptoms = symptoms
 	}
 
-	return MedicalReport{}, fmt.Errorf("report not found")
+	return view, nil
+}
+
+// hospitalReportSecret reads the treating hospital's symptom-encryption
+// key from the transient map, the same way traceCodeSecret in trace.go
+// keeps the trace code HMAC secret off the ledger.
+func hospitalReportSecret(ctx contractapi.TransactionContextInterface) ([]byte, error) {
+	transient, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transient map: %v", err)
+	}
+	secret, ok := transient[hospitalReportSecretTransientKey]
+	if !ok || len(secret) == 0 {
+		return nil, fmt.Errorf("no report secret provided in transient map under %q", hospitalReportSecretTransientKey)
+	}
+	return secret, nil
+}
+
+// encryptSymptoms AES-256-GCM encrypts symptoms under a key derived from
+// secret. The nonce is derived deterministically from the transaction ID
+// rather than crypto/rand, so every endorsing peer produces identical
+// ciphertext for the same proposal, and is prepended to the returned
+// ciphertext so decryptSymptoms (run in a later transaction, with no TxID
+// of its own to derive it from) can recover it.
+func encryptSymptoms(ctx contractapi.TransactionContextInterface, secret []byte, symptoms string) ([]byte, error) {
+	gcm, err := symptomsGCM(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := sha256.Sum256([]byte(ctx.GetStub().GetTxID()))
+	sealed := gcm.Seal(nil, nonce[:gcm.NonceSize()], []byte(symptoms), nil)
+	return append(nonce[:gcm.NonceSize()], sealed...), nil
+}
+
+// decryptSymptoms reverses encryptSymptoms, reading back the nonce
+// encryptSymptoms prepended to the ciphertext.
+func decryptSymptoms(secret, ciphertext []byte) (string, error) {
+	gcm, err := symptomsGCM(secret)
+	if err != nil {
+		return "", err
+	}
+	if len(ciphertext) < gcm.NonceSize() {
+		return "", fmt.Errorf("ciphertext too short")
+	}
+
+	nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+func symptomsGCM(secret []byte) (cipher.AEAD, error) {
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
 }
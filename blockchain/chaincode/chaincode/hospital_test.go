@@ -0,0 +1,119 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"testing"
+
+	"chaincode/chaincode"
+	"chaincode/chaincode/mocks"
+)
+
+func TestHospitalInventoryAndReportsLifecycle(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity("HospitalMSP", map[string]string{
+		"role":        "doctor",
+		"affiliation": "general",
+	}))
+	hospitalContract := &chaincode.HospitalContract{}
+
+	if err := hospitalContract.CreateHospital(ctx, "general", "555-0200"); err != nil {
+		t.Fatalf("CreateHospital returned error: %v", err)
+	}
+	if err := hospitalContract.CreateHospital(ctx, "general", "555-0200"); err == nil {
+		t.Fatalf("expected error creating duplicate hospital")
+	}
+
+	if err := hospitalContract.CreatePatientRecord(ctx, "general", "alice"); err != nil {
+		t.Fatalf("CreatePatientRecord returned error: %v", err)
+	}
+
+	patients, err := hospitalContract.GetPatients(ctx, "general")
+	if err != nil {
+		t.Fatalf("GetPatients returned error: %v", err)
+	}
+	if len(patients) != 1 || patients[0] != "alice" {
+		t.Fatalf("unexpected patients: %v", patients)
+	}
+
+	ctx.SetTransient(map[string][]byte{"symptoms": []byte("fever"), "reportSecret": []byte("general-hospital-secret")})
+	reportID, err := hospitalContract.ModifyReport(ctx, "general", "alice", []string{"aspirin"})
+	if err != nil {
+		t.Fatalf("ModifyReport returned error: %v", err)
+	}
+	if reportID != 1 {
+		t.Fatalf("expected first report to get ID 1, got %d", reportID)
+	}
+
+	report, err := hospitalContract.ViewReport(ctx, "alice", "general", reportID)
+	if err != nil {
+		t.Fatalf("ViewReport returned error: %v", err)
+	}
+	if report.Symptoms != "fever" {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	if err := hospitalContract.AddDrugToHospitalInventory(ctx, "general", "aspirin", "trace-1"); err != nil {
+		t.Fatalf("AddDrugToHospitalInventory returned error: %v", err)
+	}
+
+	traceCode, err := hospitalContract.RemoveDrugFromHospitalInventory(ctx, "general", "aspirin")
+	if err != nil {
+		t.Fatalf("RemoveDrugFromHospitalInventory returned error: %v", err)
+	}
+	if traceCode != "trace-1" {
+		t.Fatalf("expected trace-1, got %s", traceCode)
+	}
+
+	if _, err := hospitalContract.RemoveDrugFromHospitalInventory(ctx, "general", "aspirin"); err == nil {
+		t.Fatalf("expected error removing an already-removed drug")
+	}
+}
+
+func TestModifyReportDeniesCallerFromAnotherHospital(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity("HospitalMSP", map[string]string{
+		"role":        "doctor",
+		"affiliation": "general",
+	}))
+	hospitalContract := &chaincode.HospitalContract{}
+
+	if err := hospitalContract.CreateHospital(ctx, "general", "555-0200"); err != nil {
+		t.Fatalf("CreateHospital returned error: %v", err)
+	}
+	if err := hospitalContract.CreatePatientRecord(ctx, "general", "alice"); err != nil {
+		t.Fatalf("CreatePatientRecord returned error: %v", err)
+	}
+
+	ctx.SetClientIdentity(mocks.NewClientIdentity("HospitalMSP", map[string]string{
+		"role":        "doctor",
+		"affiliation": "st-mary",
+	}))
+
+	ctx.SetTransient(map[string][]byte{"symptoms": []byte("fever")})
+	if _, err := hospitalContract.ModifyReport(ctx, "general", "alice", []string{"aspirin"}); err == nil {
+		t.Fatalf("expected a doctor affiliated with st-mary to be denied writing a report at general")
+	}
+}
+
+func TestGetHospitalsListsEveryHospital(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	hospitalContract := &chaincode.HospitalContract{}
+
+	if err := hospitalContract.CreateHospital(ctx, "general", "555-0200"); err != nil {
+		t.Fatalf("CreateHospital(general) returned error: %v", err)
+	}
+	if err := hospitalContract.CreateHospital(ctx, "st-mary", "555-0201"); err != nil {
+		t.Fatalf("CreateHospital(st-mary) returned error: %v", err)
+	}
+
+	hospitals, err := hospitalContract.GetHospitals(ctx)
+	if err != nil {
+		t.Fatalf("GetHospitals returned error: %v", err)
+	}
+	if len(hospitals) != 2 {
+		t.Fatalf("expected 2 hospitals, got %d: %v", len(hospitals), hospitals)
+	}
+}
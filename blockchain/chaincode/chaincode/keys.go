@@ -0,0 +1,101 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"encoding/json"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+// Composite key object types used to index ledger state. Every entity is
+// stored under <objectType>~<attr1>~<attr2>... so that listing and range
+// queries never depend on data held outside the ledger.
+const (
+	patientKeyType          = "patient"
+	hospitalKeyType         = "hospital"
+	manufacturerKeyType     = "manufacturer"
+	hospitalPatientKeyType  = "hospitalpatient"
+	hospitalReportKeyType   = "hospitalreport"
+	hospitalDrugKeyType     = "hospitaldrug"
+	manufacturerDrugKeyType = "manufacturerdrug"
+	patientConsentKeyType   = "patientconsent"
+)
+
+// PaginatedQueryResult wraps a page of results returned by one of the
+// *Paginated chaincode functions together with the bookmark the caller
+// must pass back in to fetch the next page.
+type PaginatedQueryResult struct {
+	Records             json.RawMessage `json:"records"`
+	FetchedRecordsCount int32           `json:"fetchedRecordsCount"`
+	Bookmark            string          `json:"bookmark"`
+}
+
+// buildPaginatedResult drains iterator, unmarshals every value into the
+// shape described by out (a pointer to a slice), and wraps the page
+// together with Fabric's pagination metadata.
+func buildPaginatedResult(iterator shim.StateQueryIteratorInterface, metadata *peer.QueryResponseMetadata, out interface{}) (*PaginatedQueryResult, error) {
+	values, err := collectIteratorValues(iterator)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(values, out); err != nil {
+		return nil, err
+	}
+
+	recordsJSON, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginatedQueryResult{
+		Records:             recordsJSON,
+		FetchedRecordsCount: metadata.GetFetchedRecordsCount(),
+		Bookmark:            metadata.GetBookmark(),
+	}, nil
+}
+
+// collectIteratorValues drains a state query iterator into a single JSON
+// array of its raw values, closing the iterator once exhausted.
+func collectIteratorValues(iterator shim.StateQueryIteratorInterface) (json.RawMessage, error) {
+	defer iterator.Close()
+
+	raw := []byte("[")
+	first := true
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		if !first {
+			raw = append(raw, ',')
+		}
+		first = false
+		raw = append(raw, kv.Value...)
+	}
+	raw = append(raw, ']')
+
+	return raw, nil
+}
+
+// queryByField runs a CouchDB rich query (a Mongo-style JSON selector) and
+// unmarshals the matching documents into out (a pointer to a slice).
+func queryByField(ctx contractapi.TransactionContextInterface, selector string, out interface{}) error {
+	iterator, err := ctx.GetStub().GetQueryResult(selector)
+	if err != nil {
+		return err
+	}
+
+	values, err := collectIteratorValues(iterator)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(values, out)
+}
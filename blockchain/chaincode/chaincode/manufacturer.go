@@ -3,24 +3,19 @@ package chaincode
 import (
 	"encoding/json"
 	"fmt"
-	"sync"
-	"time"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"chaincode/chaincode/auth"
 )
 
 type ManufacturerContract struct {
 	contractapi.Contract
 }
 
-var manufacturers = map[string]*Manufacturer{}
-
 type Manufacturer struct {
-	Name      string
-	Inventory map[string]ManufacturerDrug
-	Contact   string
-	Channels  map[string]bool
-	mu        sync.Mutex
+	Name    string
+	Contact string
 }
 
 type ManufacturerDrug struct {
@@ -31,6 +26,14 @@ type ManufacturerDrug struct {
 	ProductionTime string
 }
 
+func manufacturerKey(ctx contractapi.TransactionContextInterface, name string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(manufacturerKeyType, []string{name})
+}
+
+func manufacturerDrugKey(ctx contractapi.TransactionContextInterface, manufacturerName, traceCode string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(manufacturerDrugKeyType, []string{manufacturerName, traceCode})
+}
+
 // CreateManufacturer creates a new manufacturer.
 // Parameters:
 // - ctx: the transaction context provided by Hyperledger Fabric.
@@ -38,30 +41,43 @@ type ManufacturerDrug struct {
 // - contact: the contact information of the manufacturer.
 //
 // This function checks if the manufacturer already exists. If it does, it returns an error.
-// Otherwise, it creates a new manufacturer with the provided name and contact information,
-// and initializes its inventory and channels. The manufacturer is then stored in the world state.
+// Otherwise, it creates a new manufacturer with the provided name and contact information
+// and stores it in the world state under its composite key.
 //
 // Returns:
 // - error: nil if the operation is successful, or an error message if it fails or the manufacturer already exists.
 func (mc *ManufacturerContract) CreateManufacturer(ctx contractapi.TransactionContextInterface, name, contact string) error {
-	if _, exists := manufacturers[name]; exists {
+	if err := auth.RequireRole(ctx, "manufacturer"); err != nil {
+		return err
+	}
+	if err := auth.RequireMSP(ctx, auth.ManufacturerMSP); err != nil {
+		return err
+	}
+
+	key, err := manufacturerKey(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	existing, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return fmt.Errorf("failed to read manufacturer from world state: %v", err)
+	}
+	if existing != nil {
 		return fmt.Errorf("manufacturer already exists")
 	}
 
 	manufacturer := &Manufacturer{
-		Name:      name,
-		Contact:   contact,
-		Inventory: make(map[string]ManufacturerDrug),
-		Channels:  make(map[string]bool),
+		Name:    name,
+		Contact: contact,
 	}
 
-	manufacturers[name] = manufacturer
 	manufacturerJSON, err := json.Marshal(manufacturer)
 	if err != nil {
 		return err
 	}
 
-	return ctx.GetStub().PutState(name, manufacturerJSON)
+	return ctx.GetStub().PutState(key, manufacturerJSON)
 }
 
 // AddDrugToMnfcInventory adds a new drug to the manufacturer's inventory.
@@ -69,73 +85,149 @@ func (mc *ManufacturerContract) CreateManufacturer(ctx contractapi.TransactionCo
 // - ctx: the transaction context provided by Hyperledger Fabric.
 // - manufacturerName: the name of the manufacturer producing the drug.
 // - drugName: the name of the drug to add.
-// - traceCode: the trace code of the drug.
 // - price: the price of the drug.
 //
-// This function checks if the manufacturer exists. If not, it returns an error.
-// It then adds the drug with the provided trace code to the manufacturer's inventory
-// and stores the updated manufacturer in the world state.
+// This function checks if the manufacturer exists. If not, it returns an
+// error. It then mints the drug's trace code with GenerateTraceCode, so
+// the code is HMAC-signed rather than caller-supplied, and adds the drug
+// to the manufacturer's inventory under its own composite key.
 //
 // Returns:
+// - string: the minted trace code, if the operation is successful.
 // - error: nil if the operation is successful, or an error message if it fails.
-func (mc *ManufacturerContract) AddDrugToMnfcInventory(ctx contractapi.TransactionContextInterface, manufacturerName, drugName, traceCode string, price float64) error {
-	manufacturer, exists := manufacturers[manufacturerName]
-	if !exists {
-		return fmt.Errorf("manufacturer not found")
+func (mc *ManufacturerContract) AddDrugToMnfcInventory(ctx contractapi.TransactionContextInterface, manufacturerName, drugName string, price float64) (string, error) {
+	if err := auth.RequireRole(ctx, "manufacturer"); err != nil {
+		return "", err
+	}
+	if err := auth.RequireMSP(ctx, auth.ManufacturerMSP); err != nil {
+		return "", err
+	}
+
+	valid, err := mc.ValidManufacturer(ctx, manufacturerName)
+	if err != nil {
+		return "", err
+	}
+	if !valid {
+		return "", fmt.Errorf("manufacturer not found")
+	}
+
+	txTimestamp, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return "", fmt.Errorf("failed to read transaction timestamp: %v", err)
 	}
+	productionTime := txTimestamp.AsTime().Format(timestampLayout)
 
-	manufacturer.mu.Lock()
-	defer manufacturer.mu.Unlock()
+	traceCode, err := GenerateTraceCode(ctx, drugName, manufacturerName, price, productionTime)
+	if err != nil {
+		return "", fmt.Errorf("failed to mint trace code: %v", err)
+	}
 
 	drug := ManufacturerDrug{
 		Name:           drugName,
 		TraceCode:      traceCode,
 		Manufacturer:   manufacturerName,
 		Price:          price,
-		ProductionTime: time.Now().Format(time.RFC3339),
+		ProductionTime: productionTime,
 	}
 
-	manufacturer.Inventory[traceCode] = drug
-	manufacturerJSON, err := json.Marshal(manufacturer)
+	drugJSON, err := json.Marshal(drug)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return ctx.GetStub().PutState(manufacturerName, manufacturerJSON)
+	key, err := manufacturerDrugKey(ctx, manufacturerName, traceCode)
+	if err != nil {
+		return "", err
+	}
+
+	return traceCode, ctx.GetStub().PutState(key, drugJSON)
+}
+
+// ValidManufacturer checks whether a manufacturer with the given name exists.
+func (mc *ManufacturerContract) ValidManufacturer(ctx contractapi.TransactionContextInterface, manufacturerName string) (bool, error) {
+	key, err := manufacturerKey(ctx, manufacturerName)
+	if err != nil {
+		return false, err
+	}
+
+	manufacturerJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return false, fmt.Errorf("failed to read manufacturer from world state: %v", err)
+	}
+	if manufacturerJSON == nil {
+		return false, nil
+	}
+
+	return true, nil
 }
 
+// GetManufacturers retrieves a list of all manufacturers, walking the
+// manufacturer composite key range.
 func (mc *ManufacturerContract) GetManufacturers(ctx contractapi.TransactionContextInterface) ([]string, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(manufacturerKeyType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
 	var manufacturerList []string
-	for manufacturerName := range manufacturers {
-		manufacturerList = append(manufacturerList, manufacturerName)
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		manufacturerList = append(manufacturerList, attributes[0])
 	}
+
 	return manufacturerList, nil
 }
 
+// QueryManufacturerDrugsByField runs a CouchDB Mongo-style rich query
+// selector against manufacturer inventory documents.
+func (mc *ManufacturerContract) QueryManufacturerDrugsByField(ctx contractapi.TransactionContextInterface, selector string) ([]*ManufacturerDrug, error) {
+	drugs := []*ManufacturerDrug{}
+	if err := queryByField(ctx, selector, &drugs); err != nil {
+		return nil, err
+	}
+	return drugs, nil
+}
+
 // RemoveDrugFromMnfcInventory removes a drug from the inventory and returns its trace code.
 func (mc *ManufacturerContract) RemoveDrugFromMnfcInventory(ctx contractapi.TransactionContextInterface, manufacturerName, drugName string) (string, error) {
-	manufacturer, exists := manufacturers[manufacturerName]
-	if !exists {
-		return "", fmt.Errorf("manufacturer not found")
+	if err := auth.RequireRole(ctx, "manufacturer"); err != nil {
+		return "", err
+	}
+	if err := auth.RequireMSP(ctx, auth.ManufacturerMSP); err != nil {
+		return "", err
 	}
 
-	manufacturer.mu.Lock()
-	defer manufacturer.mu.Unlock()
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(manufacturerDrugKeyType, []string{manufacturerName})
+	if err != nil {
+		return "", err
+	}
+	defer iterator.Close()
 
-	for traceCode, drug := range manufacturer.Inventory {
-		if drug.Name == drugName {
-			delete(manufacturer.Inventory, traceCode)
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return "", err
+		}
 
-			manufacturerJSON, err := json.Marshal(manufacturer)
-			if err != nil {
-				return "", err
-			}
+		var drug ManufacturerDrug
+		if err := json.Unmarshal(kv.Value, &drug); err != nil {
+			return "", err
+		}
 
-			if err := ctx.GetStub().PutState(manufacturerName, manufacturerJSON); err != nil {
+		if drug.Name == drugName {
+			if err := ctx.GetStub().DelState(kv.Key); err != nil {
 				return "", err
 			}
-
-			return traceCode, nil // 找到药品并删除后立即返回
+			return drug.TraceCode, nil // 找到药品并删除后立即返回
 		}
 	}
 
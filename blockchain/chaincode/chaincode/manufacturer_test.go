@@ -0,0 +1,88 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"testing"
+
+	"chaincode/chaincode"
+	"chaincode/chaincode/auth"
+	"chaincode/chaincode/mocks"
+)
+
+func TestManufacturerInventoryLifecycle(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity(auth.ManufacturerMSP, map[string]string{"role": "manufacturer"}))
+	ctx.SetTransient(map[string][]byte{"traceCodeSecret": []byte("hmac-secret")})
+	contract := &chaincode.ManufacturerContract{}
+
+	if err := contract.CreateManufacturer(ctx, "acme", "555-0300"); err != nil {
+		t.Fatalf("CreateManufacturer returned error: %v", err)
+	}
+	if err := contract.CreateManufacturer(ctx, "acme", "555-0300"); err == nil {
+		t.Fatalf("expected error creating duplicate manufacturer")
+	}
+
+	mintedTraceCode, err := contract.AddDrugToMnfcInventory(ctx, "acme", "aspirin", 9.99)
+	if err != nil {
+		t.Fatalf("AddDrugToMnfcInventory returned error: %v", err)
+	}
+
+	manufacturers, err := contract.GetManufacturers(ctx)
+	if err != nil {
+		t.Fatalf("GetManufacturers returned error: %v", err)
+	}
+	if len(manufacturers) != 1 || manufacturers[0] != "acme" {
+		t.Fatalf("unexpected manufacturers: %v", manufacturers)
+	}
+
+	traceCode, err := contract.RemoveDrugFromMnfcInventory(ctx, "acme", "aspirin")
+	if err != nil {
+		t.Fatalf("RemoveDrugFromMnfcInventory returned error: %v", err)
+	}
+	if traceCode != mintedTraceCode {
+		t.Fatalf("expected %s, got %s", mintedTraceCode, traceCode)
+	}
+
+	if _, err := contract.RemoveDrugFromMnfcInventory(ctx, "acme", "aspirin"); err == nil {
+		t.Fatalf("expected error removing an already-removed drug")
+	}
+}
+
+func TestCreateManufacturerDeniesNonManufacturerMSP(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity("HospitalMSP", map[string]string{"role": "manufacturer"}))
+	contract := &chaincode.ManufacturerContract{}
+
+	if err := contract.CreateManufacturer(ctx, "acme", "555-0300"); err == nil {
+		t.Fatalf("expected a caller from HospitalMSP to be denied creating a manufacturer")
+	}
+}
+
+func TestQueryManufacturerDrugsByField(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity(auth.ManufacturerMSP, map[string]string{"role": "manufacturer"}))
+	ctx.SetTransient(map[string][]byte{"traceCodeSecret": []byte("hmac-secret")})
+	contract := &chaincode.ManufacturerContract{}
+
+	if err := contract.CreateManufacturer(ctx, "acme", "555-0300"); err != nil {
+		t.Fatalf("CreateManufacturer returned error: %v", err)
+	}
+	if _, err := contract.AddDrugToMnfcInventory(ctx, "acme", "aspirin", 9.99); err != nil {
+		t.Fatalf("AddDrugToMnfcInventory(aspirin) returned error: %v", err)
+	}
+	ibuprofenTraceCode, err := contract.AddDrugToMnfcInventory(ctx, "acme", "ibuprofen", 12.5)
+	if err != nil {
+		t.Fatalf("AddDrugToMnfcInventory(ibuprofen) returned error: %v", err)
+	}
+
+	matches, err := contract.QueryManufacturerDrugsByField(ctx, `{"selector":{"Name":"ibuprofen"}}`)
+	if err != nil {
+		t.Fatalf("QueryManufacturerDrugsByField returned error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].TraceCode != ibuprofenTraceCode {
+		t.Fatalf("expected only %s to match, got %+v", ibuprofenTraceCode, matches)
+	}
+}
@@ -0,0 +1,291 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package mocks provides a lightweight in-memory stand-in for the Fabric
+// peer so the chaincode contracts can be unit tested without a running
+// network. It only implements the subset of shim.ChaincodeStubInterface
+// the contracts actually use; everything else panics on the embedded nil
+// interface if a test exercises it.
+package mocks
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go/peer"
+)
+
+const compositeKeyNamespace = "\x00"
+
+// ChaincodeStub is a minimal fake of shim.ChaincodeStubInterface backed by
+// a sorted in-memory map, so GetStateByPartialCompositeKey and range
+// queries behave the way they would against the real world state.
+type ChaincodeStub struct {
+	shim.ChaincodeStubInterface
+	TxID         string
+	TxTimestamp  *timestamp.Timestamp
+	state        map[string][]byte
+	privateState map[string]map[string][]byte
+	transient    map[string][]byte
+}
+
+// NewChaincodeStub returns an empty stub ready to be wrapped in a
+// TransactionContext.
+func NewChaincodeStub() *ChaincodeStub {
+	return &ChaincodeStub{
+		TxID:         "mock-tx",
+		TxTimestamp:  &timestamp.Timestamp{Seconds: 1700000000},
+		state:        map[string][]byte{},
+		privateState: map[string]map[string][]byte{},
+	}
+}
+
+func (cs *ChaincodeStub) GetTxID() string {
+	return cs.TxID
+}
+
+// GetTxTimestamp returns the fixed TxTimestamp a test can override, so
+// code that derives deterministic values from it (e.g. trace code
+// minting, movement records) stays testable without wall-clock time.
+func (cs *ChaincodeStub) GetTxTimestamp() (*timestamp.Timestamp, error) {
+	return cs.TxTimestamp, nil
+}
+
+func (cs *ChaincodeStub) GetState(key string) ([]byte, error) {
+	return cs.state[key], nil
+}
+
+func (cs *ChaincodeStub) PutState(key string, value []byte) error {
+	cs.state[key] = value
+	return nil
+}
+
+func (cs *ChaincodeStub) DelState(key string) error {
+	delete(cs.state, key)
+	return nil
+}
+
+// GetTransient returns the transient map a test has populated with
+// SetTransient, standing in for data a real client would send alongside
+// the proposal instead of as a public argument.
+func (cs *ChaincodeStub) GetTransient() (map[string][]byte, error) {
+	return cs.transient, nil
+}
+
+// SetTransient lets a test populate the transient map, e.g. PII bound for
+// a private data collection that a contract method expects out-of-band
+// rather than as a plain parameter.
+func (cs *ChaincodeStub) SetTransient(transient map[string][]byte) {
+	cs.transient = transient
+}
+
+// PutPrivateData writes value into collection's own keyspace, separate
+// from both public world state and every other collection.
+func (cs *ChaincodeStub) PutPrivateData(collection, key string, value []byte) error {
+	if cs.privateState[collection] == nil {
+		cs.privateState[collection] = map[string][]byte{}
+	}
+	cs.privateState[collection][key] = value
+	return nil
+}
+
+func (cs *ChaincodeStub) GetPrivateData(collection, key string) ([]byte, error) {
+	return cs.privateState[collection][key], nil
+}
+
+func (cs *ChaincodeStub) DelPrivateData(collection, key string) error {
+	delete(cs.privateState[collection], key)
+	return nil
+}
+
+// CreateCompositeKey mirrors shim's own delimiter-based encoding closely
+// enough for range scans and SplitCompositeKey to round-trip correctly.
+func (cs *ChaincodeStub) CreateCompositeKey(objectType string, attributes []string) (string, error) {
+	key := compositeKeyNamespace + objectType + compositeKeyNamespace
+	for _, attribute := range attributes {
+		key += attribute + compositeKeyNamespace
+	}
+	return key, nil
+}
+
+func (cs *ChaincodeStub) SplitCompositeKey(compositeKey string) (string, []string, error) {
+	parts := strings.Split(compositeKey, compositeKeyNamespace)
+	if len(parts) < 3 || parts[0] != "" {
+		return "", nil, fmt.Errorf("invalid composite key: %q", compositeKey)
+	}
+	objectType := parts[1]
+	attributes := parts[2 : len(parts)-1]
+	return objectType, attributes, nil
+}
+
+func (cs *ChaincodeStub) GetStateByPartialCompositeKey(objectType string, attributes []string) (shim.StateQueryIteratorInterface, error) {
+	prefix, err := cs.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return newIterator(cs.matchingKV(prefix)), nil
+}
+
+func (cs *ChaincodeStub) GetStateByPartialCompositeKeyWithPagination(objectType string, attributes []string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	prefix, err := cs.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	all := cs.matchingKV(prefix)
+
+	start := 0
+	if bookmark != "" {
+		parsed, err := strconv.Atoi(bookmark)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid bookmark: %v", err)
+		}
+		start = parsed
+	}
+
+	end := start + int(pageSize)
+	if end > len(all) || pageSize <= 0 {
+		end = len(all)
+	}
+	if start > len(all) {
+		start = len(all)
+	}
+
+	page := all[start:end]
+	nextBookmark := ""
+	if end < len(all) {
+		nextBookmark = strconv.Itoa(end)
+	}
+
+	metadata := &peer.QueryResponseMetadata{
+		FetchedRecordsCount: int32(len(page)),
+		Bookmark:            nextBookmark,
+	}
+
+	return newIterator(page), metadata, nil
+}
+
+// GetQueryResult evaluates a tiny subset of CouchDB's Mongo-style rich
+// query syntax — exact-match, $gte/$lte/$gt/$lt for comparable scalars
+// (numbers compared numerically, anything else by its string form), and
+// $regex as a string prefix check — which is all the contracts need.
+//
+// CouchDB indexes a key-value's JSON document body regardless of how its
+// key was constructed, so this scans every key in world state, including
+// composite-keyed ones: Patient, ManufacturerDrug, and HospitalDrug are
+// all stored under composite keys and still need to be queryable.
+func (cs *ChaincodeStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface, error) {
+	var parsed struct {
+		Selector map[string]interface{} `json:"selector"`
+	}
+	if err := json.Unmarshal([]byte(query), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid rich query selector: %v", err)
+	}
+
+	var matches []queryresult.KV
+	for _, key := range cs.sortedKeys() {
+		var doc map[string]interface{}
+		value := cs.state[key]
+		if err := json.Unmarshal(value, &doc); err != nil {
+			continue
+		}
+
+		if selectorMatches(parsed.Selector, doc) {
+			matches = append(matches, queryresult.KV{Key: key, Value: value})
+		}
+	}
+
+	return newIterator(matches), nil
+}
+
+func (cs *ChaincodeStub) matchingKV(prefix string) []queryresult.KV {
+	var matches []queryresult.KV
+	for _, key := range cs.sortedKeys() {
+		if strings.HasPrefix(key, prefix) {
+			matches = append(matches, queryresult.KV{Key: key, Value: cs.state[key]})
+		}
+	}
+	return matches
+}
+
+func (cs *ChaincodeStub) sortedKeys() []string {
+	keys := make([]string, 0, len(cs.state))
+	for key := range cs.state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func selectorMatches(selector map[string]interface{}, doc map[string]interface{}) bool {
+	for field, want := range selector {
+		got, exists := doc[field]
+		if !exists {
+			return false
+		}
+
+		operators, isOperators := want.(map[string]interface{})
+		if !isOperators {
+			if fmt.Sprintf("%v", got) != fmt.Sprintf("%v", want) {
+				return false
+			}
+			continue
+		}
+
+		if !operatorsMatch(operators, got) {
+			return false
+		}
+	}
+	return true
+}
+
+func operatorsMatch(operators map[string]interface{}, got interface{}) bool {
+	gotStr := fmt.Sprintf("%v", got)
+	gotNum, gotIsNum := got.(float64)
+	for op, want := range operators {
+		wantStr := fmt.Sprintf("%v", want)
+		cmp := strings.Compare(gotStr, wantStr)
+		if wantNum, wantIsNum := want.(float64); gotIsNum && wantIsNum {
+			switch {
+			case gotNum < wantNum:
+				cmp = -1
+			case gotNum > wantNum:
+				cmp = 1
+			default:
+				cmp = 0
+			}
+		}
+		switch op {
+		case "$gte":
+			if cmp < 0 {
+				return false
+			}
+		case "$lte":
+			if cmp > 0 {
+				return false
+			}
+		case "$gt":
+			if cmp <= 0 {
+				return false
+			}
+		case "$lt":
+			if cmp >= 0 {
+				return false
+			}
+		case "$regex":
+			if !strings.HasPrefix(gotStr, strings.Trim(wantStr, "^")) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+)
+
+// ClientIdentity is a fake cid.ClientIdentity driven entirely by the
+// fields a test sets, so contract and auth tests can exercise
+// attribute- and MSP-based access control without a real certificate.
+type ClientIdentity struct {
+	ID         string
+	MSPID      string
+	Attributes map[string]string
+	CommonName string
+}
+
+// NewClientIdentity returns a ClientIdentity with an initialized
+// Attributes map, ready for a test to fill in.
+func NewClientIdentity(mspID string, attributes map[string]string) *ClientIdentity {
+	if attributes == nil {
+		attributes = map[string]string{}
+	}
+	return &ClientIdentity{MSPID: mspID, Attributes: attributes}
+}
+
+var _ cid.ClientIdentity = (*ClientIdentity)(nil)
+
+func (ci *ClientIdentity) GetID() (string, error) {
+	return ci.ID, nil
+}
+
+func (ci *ClientIdentity) GetMSPID() (string, error) {
+	return ci.MSPID, nil
+}
+
+func (ci *ClientIdentity) GetAttributeValue(attrName string) (string, bool, error) {
+	value, found := ci.Attributes[attrName]
+	return value, found, nil
+}
+
+func (ci *ClientIdentity) AssertAttributeValue(attrName, attrValue string) error {
+	value, found, err := ci.GetAttributeValue(attrName)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("attribute %q not found", attrName)
+	}
+	if value != attrValue {
+		return fmt.Errorf("attribute %q has value %q, expected %q", attrName, value, attrValue)
+	}
+	return nil
+}
+
+func (ci *ClientIdentity) GetX509Certificate() (*x509.Certificate, error) {
+	return &x509.Certificate{Subject: pkix.Name{CommonName: ci.CommonName}}, nil
+}
@@ -0,0 +1,32 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import "github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+
+// kvIterator is a minimal shim.StateQueryIteratorInterface over an
+// in-memory slice of results gathered ahead of time.
+type kvIterator struct {
+	results []queryresult.KV
+	next    int
+}
+
+func newIterator(results []queryresult.KV) *kvIterator {
+	return &kvIterator{results: results}
+}
+
+func (it *kvIterator) HasNext() bool {
+	return it.next < len(it.results)
+}
+
+func (it *kvIterator) Next() (*queryresult.KV, error) {
+	kv := it.results[it.next]
+	it.next++
+	return &kv, nil
+}
+
+func (it *kvIterator) Close() error {
+	return nil
+}
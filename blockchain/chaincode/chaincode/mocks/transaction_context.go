@@ -0,0 +1,44 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mocks
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// TransactionContext is a minimal fake of
+// contractapi.TransactionContextInterface wired to an in-memory
+// ChaincodeStub, for use from contract unit tests.
+type TransactionContext struct {
+	stub           *ChaincodeStub
+	clientIdentity cid.ClientIdentity
+}
+
+// NewTransactionContext returns a transaction context backed by a fresh
+// in-memory stub.
+func NewTransactionContext() *TransactionContext {
+	return &TransactionContext{stub: NewChaincodeStub()}
+}
+
+func (tc *TransactionContext) GetStub() shim.ChaincodeStubInterface {
+	return tc.stub
+}
+
+func (tc *TransactionContext) GetClientIdentity() cid.ClientIdentity {
+	return tc.clientIdentity
+}
+
+// SetClientIdentity lets a test swap in a fake ClientIdentity to exercise
+// attribute- and role-based access control.
+func (tc *TransactionContext) SetClientIdentity(identity cid.ClientIdentity) {
+	tc.clientIdentity = identity
+}
+
+// SetTransient lets a test populate the transient map a contract method
+// reads sensitive, non-public arguments from.
+func (tc *TransactionContext) SetTransient(transient map[string][]byte) {
+	tc.stub.SetTransient(transient)
+}
@@ -5,10 +5,15 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"chaincode/chaincode/auth"
 )
 
 // PatientContract
@@ -16,12 +21,25 @@ type PatientContract struct {
 	contractapi.Contract
 }
 
-// 全局变量，存储所有病人信息
-var patients = map[string]*Patient{}
+// patientPrivateCollection holds every patient's PII. Its collection
+// policy (see collections_config.json) restricts it to members of
+// PatientMSP, whatever HomeOrg a patient's record names;
+// SharePatientDataWith is the only way another org sees it.
+const patientPrivateCollection = "patientPrivate"
 
-// Patient shows the info of patients
+// Patient is the non-sensitive record kept on public world state: enough
+// to prove a patient exists and identify who owns their PII. The fields
+// that used to live here (birth date, height, weight, gender, contact)
+// now live in patientPrivateCollection instead.
 type Patient struct {
-	Name      string
+	Name     string
+	HashedID string
+	HomeOrg  string
+}
+
+// PatientPII is the sensitive half of a patient record. It is never
+// written to public world state, only to patientPrivateCollection.
+type PatientPII struct {
 	BirthDate string
 	Height    float64
 	Weight    float64
@@ -29,48 +47,294 @@ type Patient struct {
 	Contact   string
 }
 
+// patientKey builds the composite key a patient is stored under, so a
+// restarted or freshly-joined peer can always recover it from the ledger.
+func patientKey(ctx contractapi.TransactionContextInterface, name string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(patientKeyType, []string{name})
+}
+
+// patientConsentKey builds the composite key a patient's consent to share
+// their private data with a given org is recorded under.
+func patientConsentKey(ctx contractapi.TransactionContextInterface, name, hospitalOrg string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(patientConsentKeyType, []string{name, hospitalOrg})
+}
+
+// hashPatientID derives the non-reversible identifier stored as
+// Patient.HashedID on public world state. Records are still keyed and
+// looked up by the patient's name, which is stored publicly as well.
+func hashPatientID(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+// implicitOrgCollection names the implicit private data collection
+// Fabric maintains for mspID, so a share can land in an org's own
+// collection without it being declared in collections_config.json.
+func implicitOrgCollection(mspID string) string {
+	return "_implicit_org_" + mspID
+}
+
 // CreatePatient creates a new patient record.
 // Parameters:
 // - ctx: the transaction context provided by Hyperledger Fabric.
 // - name: the name of the patient.
-// - birthDate: the birth date of the patient.
-// - height: the height of the patient.
-// - weight: the weight of the patient.
-// - gender: the gender of the patient.
-// - contact: the contact information of the patient.
+//
+// The PII fields (birthDate, height, weight, gender, contact) are not
+// plain parameters: they arrive via ctx.GetStub().GetTransient(), so they
+// never appear in the public proposal, and are written to
+// patientPrivateCollection rather than world state.
 //
 // This function checks if a patient with the given name already exists. If so, it returns an error.
-// If not, it creates a new Patient struct, adds it to the global patients map, and stores it in the world state.
+// If not, it stores a Patient struct on world state and a PatientPII struct in the private collection.
 //
 // Returns:
 // - error: nil if the operation is successful, or an error message if the patient already exists or there is an error during storage.
-func (pc *PatientContract) CreatePatient(ctx contractapi.TransactionContextInterface, name, birthDate string, height, weight float64, gender, contact string) error {
-	if _, exists := patients[name]; exists {
+func (pc *PatientContract) CreatePatient(ctx contractapi.TransactionContextInterface, name string) error {
+	key, err := patientKey(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	existing, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return fmt.Errorf("failed to read patient from world state: %v", err)
+	}
+	if existing != nil {
 		return fmt.Errorf("patient already exists")
 	}
 
+	pii, err := patientPIIFromTransient(ctx)
+	if err != nil {
+		return err
+	}
+
+	homeOrg, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return fmt.Errorf("failed to read caller's MSP ID: %v", err)
+	}
+
 	patient := &Patient{
-		Name:      name,
-		BirthDate: birthDate,
+		Name:     name,
+		HashedID: hashPatientID(name),
+		HomeOrg:  homeOrg,
+	}
+
+	patientJSON, err := json.Marshal(patient)
+	if err != nil {
+		return err
+	}
+	if err := ctx.GetStub().PutState(key, patientJSON); err != nil {
+		return err
+	}
+
+	piiJSON, err := json.Marshal(pii)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutPrivateData(patientPrivateCollection, key, piiJSON)
+}
+
+// patientPIIFromTransient reads a patient's sensitive fields out of the
+// transaction's transient map.
+func patientPIIFromTransient(ctx contractapi.TransactionContextInterface) (*PatientPII, error) {
+	transient, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transient data: %v", err)
+	}
+
+	height, err := parseTransientFloat(transient, "height")
+	if err != nil {
+		return nil, err
+	}
+	weight, err := parseTransientFloat(transient, "weight")
+	if err != nil {
+		return nil, err
+	}
+
+	return &PatientPII{
+		BirthDate: string(transient["birthDate"]),
 		Height:    height,
 		Weight:    weight,
-		Gender:    gender,
-		Contact:   contact,
+		Gender:    string(transient["gender"]),
+		Contact:   string(transient["contact"]),
+	}, nil
+}
+
+func parseTransientFloat(transient map[string][]byte, field string) (float64, error) {
+	raw, ok := transient[field]
+	if !ok || len(raw) == 0 {
+		return 0, nil
 	}
+	value, err := strconv.ParseFloat(string(raw), 64)
+	if err != nil {
+		return 0, fmt.Errorf("transient field %q is not a number: %v", field, err)
+	}
+	return value, nil
+}
 
-	patients[name] = patient
-	patientJSON, err := json.Marshal(patient)
+// GetPatient returns the public patient record stored under name: just
+// the hashed ID and home org, never the PII behind it.
+func (pc *PatientContract) GetPatient(ctx contractapi.TransactionContextInterface, name string) (*Patient, error) {
+	key, err := patientKey(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	patientJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read patient from world state: %v", err)
+	}
+	if patientJSON == nil {
+		return nil, fmt.Errorf("patient not found")
+	}
+
+	var patient Patient
+	if err := json.Unmarshal(patientJSON, &patient); err != nil {
+		return nil, err
+	}
+
+	return &patient, nil
+}
+
+// GetPatientPII returns the private half of a patient's record. It reads
+// patientPrivateCollection only, so it only succeeds on a peer holding
+// that collection; the copy SharePatientDataWith writes into another
+// org's implicit collection is not read here.
+func (pc *PatientContract) GetPatientPII(ctx contractapi.TransactionContextInterface, name string) (*PatientPII, error) {
+	key, err := patientKey(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	piiJSON, err := ctx.GetStub().GetPrivateData(patientPrivateCollection, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read patient PII from private data: %v", err)
+	}
+	if piiJSON == nil {
+		return nil, fmt.Errorf("no private data found for patient %q", name)
+	}
+
+	var pii PatientPII
+	if err := json.Unmarshal(piiJSON, &pii); err != nil {
+		return nil, err
+	}
+	return &pii, nil
+}
+
+// GrantConsent records that name consents to share their private data
+// with hospitalOrg. Only the patient themself, identified by the CN on
+// their own certificate, may grant consent on their own behalf.
+func (pc *PatientContract) GrantConsent(ctx contractapi.TransactionContextInterface, name, hospitalOrg string) error {
+	callerCN, err := auth.CallerCommonName(ctx)
+	if err != nil {
+		return err
+	}
+	if callerCN != name {
+		return fmt.Errorf("only patient %q may grant consent on their own behalf", name)
+	}
+
+	key, err := patientConsentKey(ctx, name, hospitalOrg)
+	if err != nil {
+		return err
+	}
+	return ctx.GetStub().PutState(key, []byte("granted"))
+}
+
+// hasConsented reports whether name has previously granted hospitalOrg
+// consent to receive a copy of their private data.
+func (pc *PatientContract) hasConsented(ctx contractapi.TransactionContextInterface, name, hospitalOrg string) (bool, error) {
+	key, err := patientConsentKey(ctx, name, hospitalOrg)
+	if err != nil {
+		return false, err
+	}
+	value, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return false, err
+	}
+	return value != nil, nil
+}
+
+// SharePatientDataWith grants hospitalOrg read access to name's private
+// data, by writing a copy of it into hospitalOrg's own implicit
+// collection, after confirming the patient has consented to the share.
+func (pc *PatientContract) SharePatientDataWith(ctx contractapi.TransactionContextInterface, name, hospitalOrg string) error {
+	consented, err := pc.hasConsented(ctx, name, hospitalOrg)
+	if err != nil {
+		return err
+	}
+	if !consented {
+		return fmt.Errorf("patient %q has not consented to share their data with %q", name, hospitalOrg)
+	}
+
+	key, err := patientKey(ctx, name)
 	if err != nil {
 		return err
 	}
 
-	return ctx.GetStub().PutState(name, patientJSON)
+	piiJSON, err := ctx.GetStub().GetPrivateData(patientPrivateCollection, key)
+	if err != nil {
+		return fmt.Errorf("failed to read patient PII from private data: %v", err)
+	}
+	if piiJSON == nil {
+		return fmt.Errorf("no private data found for patient %q", name)
+	}
+
+	return ctx.GetStub().PutPrivateData(implicitOrgCollection(hospitalOrg), key, piiJSON)
 }
 
+// GetPatients returns the name of every patient on the ledger, walking the
+// patient composite key range instead of a package-level map so the result
+// is correct on any peer regardless of which endorser created the record.
 func (pc *PatientContract) GetPatients(ctx contractapi.TransactionContextInterface) ([]string, error) {
+	iterator, err := ctx.GetStub().GetStateByPartialCompositeKey(patientKeyType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+
 	var patientList []string
-	for patientName := range patients {
-		patientList = append(patientList, patientName)
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		patientList = append(patientList, attributes[0])
 	}
+
 	return patientList, nil
 }
+
+// GetPatientsPaginated returns one page of patients, starting at bookmark
+// (pass "" for the first page). Callers keep requesting pages, feeding the
+// returned bookmark back in, until a page comes back with an empty
+// bookmark or with FetchedRecordsCount 0; an empty bookmark passed back
+// in starts again from the first page.
+func (pc *PatientContract) GetPatientsPaginated(ctx contractapi.TransactionContextInterface, bookmark string, pageSize int32) (*PaginatedQueryResult, error) {
+	iterator, metadata, err := ctx.GetStub().GetStateByPartialCompositeKeyWithPagination(patientKeyType, []string{}, pageSize, bookmark)
+	if err != nil {
+		return nil, err
+	}
+
+	patients := []*Patient{}
+	return buildPaginatedResult(iterator, metadata, &patients)
+}
+
+// QueryPatientsByField runs a CouchDB Mongo-style rich query selector
+// (e.g. `{"selector":{"HomeOrg":"PatientMSP"}}`) against the public
+// patient documents and returns every match. Since PII moved to
+// patientPrivateCollection, this can only filter on Name/HashedID/HomeOrg, not
+// on the sensitive fields it used to expose. It requires a CouchDB state
+// database; it will fail against LevelDB.
+func (pc *PatientContract) QueryPatientsByField(ctx contractapi.TransactionContextInterface, selector string) ([]*Patient, error) {
+	patients := []*Patient{}
+	if err := queryByField(ctx, selector, &patients); err != nil {
+		return nil, err
+	}
+	return patients, nil
+}
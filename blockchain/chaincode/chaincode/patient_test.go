@@ -0,0 +1,191 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"testing"
+
+	"chaincode/chaincode"
+	"chaincode/chaincode/auth"
+	"chaincode/chaincode/mocks"
+)
+
+func alicePII() map[string][]byte {
+	return map[string][]byte{
+		"birthDate": []byte("1990-05-01"),
+		"height":    []byte("170"),
+		"weight":    []byte("60"),
+		"gender":    []byte("F"),
+		"contact":   []byte("555-0100"),
+	}
+}
+
+func TestCreateAndGetPatient(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity(auth.PatientMSP, nil))
+	ctx.SetTransient(alicePII())
+	contract := &chaincode.PatientContract{}
+
+	if err := contract.CreatePatient(ctx, "alice"); err != nil {
+		t.Fatalf("CreatePatient returned error: %v", err)
+	}
+
+	if err := contract.CreatePatient(ctx, "alice"); err == nil {
+		t.Fatalf("expected error creating duplicate patient")
+	}
+
+	patient, err := contract.GetPatient(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetPatient returned error: %v", err)
+	}
+	if patient.Name != "alice" || patient.HomeOrg != auth.PatientMSP || patient.HashedID == "" {
+		t.Fatalf("unexpected patient: %+v", patient)
+	}
+
+	pii, err := contract.GetPatientPII(ctx, "alice")
+	if err != nil {
+		t.Fatalf("GetPatientPII returned error: %v", err)
+	}
+	if pii.Contact != "555-0100" || pii.Gender != "F" {
+		t.Fatalf("unexpected patient PII: %+v", pii)
+	}
+}
+
+func TestGetPatientsListsEveryPatient(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity(auth.PatientMSP, nil))
+	ctx.SetTransient(alicePII())
+	contract := &chaincode.PatientContract{}
+
+	if err := contract.CreatePatient(ctx, "alice"); err != nil {
+		t.Fatalf("CreatePatient(alice) returned error: %v", err)
+	}
+	if err := contract.CreatePatient(ctx, "bob"); err != nil {
+		t.Fatalf("CreatePatient(bob) returned error: %v", err)
+	}
+
+	patients, err := contract.GetPatients(ctx)
+	if err != nil {
+		t.Fatalf("GetPatients returned error: %v", err)
+	}
+	if len(patients) != 2 {
+		t.Fatalf("expected 2 patients, got %d: %v", len(patients), patients)
+	}
+}
+
+func TestGetPatientsPaginated(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity(auth.PatientMSP, nil))
+	ctx.SetTransient(alicePII())
+	contract := &chaincode.PatientContract{}
+
+	names := []string{"alice", "bob", "carol"}
+	for _, name := range names {
+		if err := contract.CreatePatient(ctx, name); err != nil {
+			t.Fatalf("CreatePatient(%s) returned error: %v", name, err)
+		}
+	}
+
+	page, err := contract.GetPatientsPaginated(ctx, "", 2)
+	if err != nil {
+		t.Fatalf("GetPatientsPaginated returned error: %v", err)
+	}
+	if page.FetchedRecordsCount != 2 {
+		t.Fatalf("expected first page of 2, got %d", page.FetchedRecordsCount)
+	}
+	if page.Bookmark == "" {
+		t.Fatalf("expected a bookmark for the remaining page")
+	}
+
+	next, err := contract.GetPatientsPaginated(ctx, page.Bookmark, 2)
+	if err != nil {
+		t.Fatalf("GetPatientsPaginated (page 2) returned error: %v", err)
+	}
+	if next.FetchedRecordsCount != 1 {
+		t.Fatalf("expected final page of 1, got %d", next.FetchedRecordsCount)
+	}
+}
+
+func TestQueryPatientsByField(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	contract := &chaincode.PatientContract{}
+
+	ctx.SetClientIdentity(mocks.NewClientIdentity(auth.PatientMSP, nil))
+	ctx.SetTransient(alicePII())
+	if err := contract.CreatePatient(ctx, "alice"); err != nil {
+		t.Fatalf("CreatePatient(alice) returned error: %v", err)
+	}
+
+	ctx.SetClientIdentity(mocks.NewClientIdentity("OtherPatientMSP", nil))
+	ctx.SetTransient(alicePII())
+	if err := contract.CreatePatient(ctx, "bob"); err != nil {
+		t.Fatalf("CreatePatient(bob) returned error: %v", err)
+	}
+
+	matches, err := contract.QueryPatientsByField(ctx, `{"selector":{"HomeOrg":"OtherPatientMSP"}}`)
+	if err != nil {
+		t.Fatalf("QueryPatientsByField returned error: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Name != "bob" {
+		t.Fatalf("expected only bob to match, got %+v", matches)
+	}
+}
+
+func TestSharePatientDataWithRequiresConsent(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity(auth.PatientMSP, nil))
+	ctx.SetTransient(alicePII())
+	contract := &chaincode.PatientContract{}
+
+	if err := contract.CreatePatient(ctx, "alice"); err != nil {
+		t.Fatalf("CreatePatient returned error: %v", err)
+	}
+
+	if err := contract.SharePatientDataWith(ctx, "alice", "HospitalMSP"); err == nil {
+		t.Fatalf("expected share to be denied without patient consent")
+	}
+}
+
+func TestGrantConsentAndSharePatientData(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity(auth.PatientMSP, nil))
+	ctx.SetTransient(alicePII())
+	contract := &chaincode.PatientContract{}
+
+	if err := contract.CreatePatient(ctx, "alice"); err != nil {
+		t.Fatalf("CreatePatient returned error: %v", err)
+	}
+
+	aliceIdentity := mocks.NewClientIdentity(auth.PatientMSP, nil)
+	aliceIdentity.CommonName = "alice"
+	ctx.SetClientIdentity(aliceIdentity)
+
+	if err := contract.GrantConsent(ctx, "alice", "HospitalMSP"); err != nil {
+		t.Fatalf("GrantConsent returned error: %v", err)
+	}
+
+	if err := contract.SharePatientDataWith(ctx, "alice", "HospitalMSP"); err != nil {
+		t.Fatalf("SharePatientDataWith returned error: %v", err)
+	}
+}
+
+func TestGrantConsentDeniesImpersonation(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetClientIdentity(mocks.NewClientIdentity(auth.PatientMSP, nil))
+	ctx.SetTransient(alicePII())
+	contract := &chaincode.PatientContract{}
+
+	if err := contract.CreatePatient(ctx, "alice"); err != nil {
+		t.Fatalf("CreatePatient returned error: %v", err)
+	}
+
+	bobIdentity := mocks.NewClientIdentity(auth.PatientMSP, nil)
+	bobIdentity.CommonName = "bob"
+	ctx.SetClientIdentity(bobIdentity)
+
+	if err := contract.GrantConsent(ctx, "alice", "HospitalMSP"); err == nil {
+		t.Fatalf("expected bob to be denied granting consent on alice's behalf")
+	}
+}
@@ -0,0 +1,325 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+
+	"chaincode/chaincode/auth"
+)
+
+// SupplyChainContract chains the manufacturer and hospital contracts
+// together into atomic shipment/receipt/dispense transactions, so a
+// client no longer has to call RemoveDrugFromMnfcInventory and
+// AddDrugToHospitalInventory separately and hope nothing fails in between.
+type SupplyChainContract struct {
+	contractapi.Contract
+}
+
+// DrugMovement is an immutable record of a drug's trace code changing
+// hands, appended to on every Ship/Receive/Dispense so GetDrugHistory can
+// reconstruct the full chain of custody.
+type DrugMovement struct {
+	TraceCode string
+	From      string
+	To        string
+	Timestamp string
+	TxID      string
+}
+
+// pendingShipment is what ShipDrug records while a drug is in transit and
+// ReceiveDrug consumes once the hospital confirms receipt.
+type pendingShipment struct {
+	DrugName         string
+	ManufacturerName string
+}
+
+const (
+	movementKeyType        = "movement"
+	pendingShipmentKeyType = "pendingshipment"
+)
+
+func movementKey(ctx contractapi.TransactionContextInterface, traceCode, txID string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(movementKeyType, []string{traceCode, txID})
+}
+
+func pendingShipmentKey(ctx contractapi.TransactionContextInterface, hospitalName, traceCode string) (string, error) {
+	return ctx.GetStub().CreateCompositeKey(pendingShipmentKeyType, []string{hospitalName, traceCode})
+}
+
+// recordMovement appends an immutable DrugMovement entry and emits a
+// chaincode event carrying the same details, so off-chain listeners and
+// on-chain history queries both see every hop a drug takes.
+func recordMovement(ctx contractapi.TransactionContextInterface, eventName, traceCode, from, to string) error {
+	txID := ctx.GetStub().GetTxID()
+	timestamp, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return fmt.Errorf("failed to read transaction timestamp: %v", err)
+	}
+
+	movement := DrugMovement{
+		TraceCode: traceCode,
+		From:      from,
+		To:        to,
+		Timestamp: timestamp.AsTime().Format(timestampLayout),
+		TxID:      txID,
+	}
+
+	movementJSON, err := json.Marshal(movement)
+	if err != nil {
+		return err
+	}
+
+	key, err := movementKey(ctx, traceCode, txID)
+	if err != nil {
+		return err
+	}
+	if err := ctx.GetStub().PutState(key, movementJSON); err != nil {
+		return err
+	}
+
+	return ctx.GetStub().SetEvent(eventName, movementJSON)
+}
+
+// ShipDrug removes a drug from the manufacturer's inventory and records it
+// as in transit to hospitalName, emitting a DrugShipped event. The drug
+// does not land in the hospital's own inventory until ReceiveDrug confirms
+// it arrived.
+func (sc *SupplyChainContract) ShipDrug(ctx contractapi.TransactionContextInterface, manufacturerName, hospitalName, drugName string) (string, error) {
+	if err := auth.RequireRole(ctx, "manufacturer"); err != nil {
+		return "", err
+	}
+	if err := auth.RequireMSP(ctx, auth.ManufacturerMSP); err != nil {
+		return "", err
+	}
+
+	manufacturerContract := &ManufacturerContract{}
+	traceCode, err := manufacturerContract.RemoveDrugFromMnfcInventory(ctx, manufacturerName, drugName)
+	if err != nil {
+		return "", err
+	}
+
+	hospitalContract := &HospitalContract{}
+	valid, err := hospitalContract.ValidHospital(ctx, hospitalName)
+	if err != nil {
+		return "", err
+	}
+	if !valid {
+		return "", fmt.Errorf("hospital not found")
+	}
+
+	shipment := pendingShipment{DrugName: drugName, ManufacturerName: manufacturerName}
+	shipmentJSON, err := json.Marshal(shipment)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := pendingShipmentKey(ctx, hospitalName, traceCode)
+	if err != nil {
+		return "", err
+	}
+	if err := ctx.GetStub().PutState(key, shipmentJSON); err != nil {
+		return "", err
+	}
+
+	if err := recordMovement(ctx, "DrugShipped", traceCode, manufacturerName, hospitalName); err != nil {
+		return "", err
+	}
+
+	return traceCode, nil
+}
+
+// ReceiveDrug confirms a hospital has taken possession of a shipped drug,
+// moving it out of the in-transit record and into the hospital's own
+// inventory, and emits a DrugReceived event.
+func (sc *SupplyChainContract) ReceiveDrug(ctx contractapi.TransactionContextInterface, hospitalName, traceCode string) error {
+	if err := auth.RequireRole(ctx, "doctor"); err != nil {
+		return err
+	}
+	if err := auth.RequireAffiliation(ctx, hospitalName); err != nil {
+		return err
+	}
+	if err := auth.RequireMSP(ctx, auth.HospitalMSP); err != nil {
+		return err
+	}
+
+	key, err := pendingShipmentKey(ctx, hospitalName, traceCode)
+	if err != nil {
+		return err
+	}
+
+	shipmentJSON, err := ctx.GetStub().GetState(key)
+	if err != nil {
+		return fmt.Errorf("failed to read pending shipment from world state: %v", err)
+	}
+	if shipmentJSON == nil {
+		return fmt.Errorf("no drug shipment to %s pending for trace code %s", hospitalName, traceCode)
+	}
+
+	var shipment pendingShipment
+	if err := json.Unmarshal(shipmentJSON, &shipment); err != nil {
+		return err
+	}
+
+	if err := ctx.GetStub().DelState(key); err != nil {
+		return err
+	}
+
+	hospitalContract := &HospitalContract{}
+	if err := hospitalContract.AddDrugToHospitalInventory(ctx, hospitalName, shipment.DrugName, traceCode); err != nil {
+		return err
+	}
+
+	return recordMovement(ctx, "DrugReceived", traceCode, shipment.ManufacturerName, hospitalName)
+}
+
+// DispenseDrug removes a drug from a hospital's inventory to fulfil an
+// existing medical report, attributing it to the treating patient and
+// emitting a DrugDispensed event.
+func (sc *SupplyChainContract) DispenseDrug(ctx contractapi.TransactionContextInterface, hospitalName, patientName, traceCode string, reportID int) error {
+	if err := auth.RequireRole(ctx, "doctor"); err != nil {
+		return err
+	}
+	if err := auth.RequireAffiliation(ctx, hospitalName); err != nil {
+		return err
+	}
+	if err := auth.RequireMSP(ctx, auth.HospitalMSP); err != nil {
+		return err
+	}
+
+	hospitalContract := &HospitalContract{}
+	report, err := hospitalContract.ViewReport(ctx, patientName, hospitalName, reportID)
+	if err != nil {
+		return err
+	}
+
+	drugKey, err := hospitalDrugKey(ctx, hospitalName, traceCode)
+	if err != nil {
+		return err
+	}
+
+	drugJSON, err := ctx.GetStub().GetState(drugKey)
+	if err != nil {
+		return fmt.Errorf("failed to read hospital drug from world state: %v", err)
+	}
+	if drugJSON == nil {
+		return fmt.Errorf("drug %s not available in %s inventory", traceCode, hospitalName)
+	}
+
+	var drug HospitalDrug
+	if err := json.Unmarshal(drugJSON, &drug); err != nil {
+		return err
+	}
+
+	drugNeeded := false
+	for _, neededDrug := range report.NeededDrugs {
+		if neededDrug == drug.Name {
+			drugNeeded = true
+			break
+		}
+	}
+	if !drugNeeded {
+		return fmt.Errorf("report %d for %s does not call for %s", reportID, patientName, drug.Name)
+	}
+
+	if err := ctx.GetStub().DelState(drugKey); err != nil {
+		return err
+	}
+
+	return recordMovement(ctx, "DrugDispensed", traceCode, hospitalName, patientName)
+}
+
+// GetDrugHistory reconstructs a drug's full provenance chain, from the
+// manufacturer who minted its trace code through every shipment, receipt,
+// and dispensing movement recorded against it.
+func (sc *SupplyChainContract) GetDrugHistory(ctx contractapi.TransactionContextInterface, traceCode string) (*DrugHistory, error) {
+	decoded, err := DecodeTraceCode(ctx, traceCode)
+	if err != nil {
+		return nil, err
+	}
+
+	mintKey, err := manufacturerDrugKey(ctx, decoded.Manufacturer, traceCode)
+	if err != nil {
+		return nil, err
+	}
+
+	mintIterator, err := ctx.GetStub().GetHistoryForKey(mintKey)
+	if err != nil {
+		return nil, err
+	}
+	defer mintIterator.Close()
+
+	var mintHistory []*DrugMintEvent
+	for mintIterator.HasNext() {
+		mod, err := mintIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		mintHistory = append(mintHistory, &DrugMintEvent{
+			TxID:      mod.GetTxId(),
+			Timestamp: mod.GetTimestamp().AsTime().Format(timestampLayout),
+			IsDelete:  mod.GetIsDelete(),
+			Value:     mod.GetValue(),
+		})
+	}
+
+	movementIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(movementKeyType, []string{traceCode})
+	if err != nil {
+		return nil, err
+	}
+	defer movementIterator.Close()
+
+	var movements []*DrugMovement
+	for movementIterator.HasNext() {
+		kv, err := movementIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var movement DrugMovement
+		if err := json.Unmarshal(kv.Value, &movement); err != nil {
+			return nil, err
+		}
+		movements = append(movements, &movement)
+	}
+
+	return &DrugHistory{
+		TraceCode:    traceCode,
+		DrugName:     decoded.DrugName,
+		Manufacturer: decoded.Manufacturer,
+		MintHistory:  mintHistory,
+		Movements:    movements,
+	}, nil
+}
+
+// DrugMintEvent is one entry in a manufacturer drug record's ledger
+// history, i.e. the raw PutState/DelState history Fabric itself keeps for
+// the key the drug was originally minted under.
+type DrugMintEvent struct {
+	TxID      string
+	Timestamp string
+	IsDelete  bool
+	Value     []byte
+}
+
+// DrugHistory is the full provenance chain GetDrugHistory returns: the raw
+// ledger history of the manufacturer inventory entry the drug was minted
+// into, plus every shipment/receipt/dispense movement recorded against its
+// trace code afterwards.
+type DrugHistory struct {
+	TraceCode    string
+	DrugName     string
+	Manufacturer string
+	MintHistory  []*DrugMintEvent
+	Movements    []*DrugMovement
+}
+
+// timestampLayout is the RFC3339 layout movement and mint-history
+// timestamps are formatted with, matching ManufacturerDrug.ProductionTime.
+const timestampLayout = "2006-01-02T15:04:05Z07:00"
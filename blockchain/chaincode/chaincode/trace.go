@@ -0,0 +1,201 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// traceCodeVersion1 is the only payload layout produced so far. Bumping it
+// for a future format keeps DecodeTraceCode able to tell old and new
+// payloads apart.
+const traceCodeVersion1 = 1
+
+const (
+	// traceCodeSecretTransientKey is the key a caller minting a trace code
+	// passes the per-channel HMAC secret under, via the transient map so
+	// it is never written to the ledger.
+	traceCodeSecretTransientKey = "traceCodeSecret"
+	// traceCodeSecretKey is the key the same secret is expected under in
+	// a caller's org implicit private data collection, for callers that
+	// only need to verify a trace code and have no transient input to give.
+	traceCodeSecretKey = "hmacSecret"
+)
+
+// ErrInvalidSignature is returned by DecodeTraceCode when a trace code's
+// HMAC tag does not match its payload.
+var ErrInvalidSignature = errors.New("trace code signature is invalid")
+
+var traceCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TraceCode is the decoded payload carried by a drug's trace code.
+// Verified is true only when DecodeTraceCode checked an HMAC tag over
+// every other field; a legacy, pre-signing trace code decodes with
+// Verified false, and callers that must not trust caller-supplied fields
+// (VerifyTraceCode) are expected to reject those.
+type TraceCode struct {
+	Version        uint8
+	DrugName       string
+	Manufacturer   string
+	Price          float64
+	ProductionTime string
+	TxID           string
+	Verified       bool
+}
+
+// signedTraceCodePayload is the struct actually CBOR-encoded and signed;
+// kept distinct from TraceCode so the wire format can evolve independently
+// of the type contracts hand around internally.
+type signedTraceCodePayload struct {
+	Version        uint8
+	DrugName       string
+	Manufacturer   string
+	Price          float64
+	ProductionTime string
+	TxID           string
+}
+
+// GenerateTraceCode builds a versioned, HMAC-signed, base32-encoded trace
+// code for a freshly produced drug. The signature binds every field
+// together so a trace code cannot be forged, nor have its fields swapped,
+// without invalidating it.
+//
+// Every endorsing peer must compute identical bytes for the same
+// proposal, so nothing here may come from a non-deterministic source
+// like time.Now() or math/rand: the per-drug uniqueness comes from
+// ctx.GetStub().GetTxID(), the same deterministic, ledger-committed
+// value recordMovement keys drug movements under in supplychain.go.
+func GenerateTraceCode(ctx contractapi.TransactionContextInterface, drugName, manufacturer string, price float64, productionTime string) (string, error) {
+	payload := signedTraceCodePayload{
+		Version:        traceCodeVersion1,
+		DrugName:       drugName,
+		Manufacturer:   manufacturer,
+		Price:          price,
+		ProductionTime: productionTime,
+		TxID:           ctx.GetStub().GetTxID(),
+	}
+
+	payloadBytes, err := cbor.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := traceCodeSecret(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payloadBytes)
+	tag := mac.Sum(nil)
+
+	return traceCodeEncoding.EncodeToString(append(payloadBytes, tag...)), nil
+}
+
+// DecodeTraceCode decodes and verifies a trace code produced by
+// GenerateTraceCode, returning ErrInvalidSignature if the HMAC tag does
+// not match. Codes that are not valid base32, or too short to carry a
+// signature, are assumed to predate this format and are parsed with
+// decodeLegacyTraceCode instead.
+func DecodeTraceCode(ctx contractapi.TransactionContextInterface, traceCode string) (*TraceCode, error) {
+	raw, err := traceCodeEncoding.DecodeString(traceCode)
+	if err != nil || len(raw) <= sha256.Size {
+		return decodeLegacyTraceCode(traceCode)
+	}
+
+	payloadBytes := raw[:len(raw)-sha256.Size]
+	tag := raw[len(raw)-sha256.Size:]
+
+	secret, err := traceCodeSecret(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payloadBytes)
+	expected := mac.Sum(nil)
+	if !hmac.Equal(expected, tag) {
+		return nil, ErrInvalidSignature
+	}
+
+	var payload signedTraceCodePayload
+	if err := cbor.Unmarshal(payloadBytes, &payload); err != nil {
+		return nil, fmt.Errorf("invalid trace code payload: %v", err)
+	}
+
+	return &TraceCode{
+		Version:        payload.Version,
+		DrugName:       payload.DrugName,
+		Manufacturer:   payload.Manufacturer,
+		Price:          payload.Price,
+		ProductionTime: payload.ProductionTime,
+		TxID:           payload.TxID,
+		Verified:       true,
+	}, nil
+}
+
+// decodeLegacyTraceCode parses the pre-migration
+// "drugName-manufacturer-price-productionTime-randInt" format. It carries
+// no signature, so its result is returned with Verified false: none of
+// its fields have been checked against tampering.
+func decodeLegacyTraceCode(traceCode string) (*TraceCode, error) {
+	parts := strings.Split(traceCode, "-")
+	if len(parts) < 5 {
+		return nil, fmt.Errorf("invalid trace code format")
+	}
+
+	price, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price format")
+	}
+
+	return &TraceCode{
+		DrugName:       parts[0],
+		Manufacturer:   parts[1],
+		Price:          price,
+		ProductionTime: strings.Join(parts[3:len(parts)-1], "-"),
+		Verified:       false,
+	}, nil
+}
+
+// traceCodeSecret resolves the per-channel HMAC secret used to sign and
+// verify trace codes. A caller minting a trace code passes it through the
+// transient map so it never touches the ledger; a caller only verifying
+// one may instead rely on the copy held in their own org's implicit
+// private data collection.
+func traceCodeSecret(ctx contractapi.TransactionContextInterface) ([]byte, error) {
+	transient, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transient map: %v", err)
+	}
+	if secret, ok := transient[traceCodeSecretTransientKey]; ok && len(secret) > 0 {
+		return secret, nil
+	}
+
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read caller's MSP ID: %v", err)
+	}
+
+	collection := "_implicit_org_" + mspID
+	secret, err := ctx.GetStub().GetPrivateData(collection, traceCodeSecretKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trace code secret from %s: %v", collection, err)
+	}
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("no trace code secret available in transient map or %s", collection)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,113 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"testing"
+
+	"chaincode/chaincode"
+	"chaincode/chaincode/mocks"
+)
+
+func TestGenerateAndDecodeTraceCodeRoundTrips(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetTransient(map[string][]byte{"traceCodeSecret": []byte("hmac-secret")})
+
+	traceCode, err := chaincode.GenerateTraceCode(ctx, "aspirin", "acme", 9.99, "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("GenerateTraceCode returned error: %v", err)
+	}
+
+	decoded, err := chaincode.DecodeTraceCode(ctx, traceCode)
+	if err != nil {
+		t.Fatalf("DecodeTraceCode returned error: %v", err)
+	}
+	if decoded.DrugName != "aspirin" || decoded.Manufacturer != "acme" || decoded.Price != 9.99 {
+		t.Fatalf("unexpected decoded trace code: %+v", decoded)
+	}
+	if !decoded.Verified {
+		t.Fatalf("expected a signed trace code to decode as Verified")
+	}
+}
+
+func TestDecodeTraceCodeRejectsWrongSecret(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetTransient(map[string][]byte{"traceCodeSecret": []byte("hmac-secret")})
+
+	traceCode, err := chaincode.GenerateTraceCode(ctx, "aspirin", "acme", 9.99, "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("GenerateTraceCode returned error: %v", err)
+	}
+
+	ctx.SetTransient(map[string][]byte{"traceCodeSecret": []byte("different-secret")})
+	if _, err := chaincode.DecodeTraceCode(ctx, traceCode); err != chaincode.ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature verifying with the wrong secret, got %v", err)
+	}
+}
+
+func TestDecodeTraceCodeRejectsTamperedPayload(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetTransient(map[string][]byte{"traceCodeSecret": []byte("hmac-secret")})
+
+	traceCode, err := chaincode.GenerateTraceCode(ctx, "aspirin", "acme", 9.99, "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("GenerateTraceCode returned error: %v", err)
+	}
+
+	mutated := []rune(traceCode)
+	flipIndex := 0
+	if mutated[flipIndex] != 'A' {
+		mutated[flipIndex] = 'A'
+	} else {
+		mutated[flipIndex] = 'B'
+	}
+
+	if _, err := chaincode.DecodeTraceCode(ctx, string(mutated)); err != chaincode.ErrInvalidSignature {
+		t.Fatalf("expected ErrInvalidSignature decoding a mutated trace code, got %v", err)
+	}
+}
+
+func TestDecodeTraceCodeFallsBackToLegacyFormat(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+
+	decoded, err := chaincode.DecodeTraceCode(ctx, "aspirin-acme-9.99-2024-01-01-42")
+	if err != nil {
+		t.Fatalf("DecodeTraceCode returned error on legacy format: %v", err)
+	}
+	if decoded.DrugName != "aspirin" || decoded.Manufacturer != "acme" {
+		t.Fatalf("unexpected decoded legacy trace code: %+v", decoded)
+	}
+	if decoded.Verified {
+		t.Fatalf("expected an unsigned legacy trace code to decode as not Verified")
+	}
+}
+
+func TestVerifyTraceCodeRejectsUnsignedLegacyCode(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	hospitalContract := &chaincode.HospitalContract{}
+
+	if _, err := hospitalContract.VerifyTraceCode(ctx, "aspirin-acme-9.99-2024-01-01-42"); err == nil {
+		t.Fatalf("expected VerifyTraceCode to reject an unsigned legacy trace code")
+	}
+}
+
+func TestVerifyTraceCodeAcceptsSignedCode(t *testing.T) {
+	ctx := mocks.NewTransactionContext()
+	ctx.SetTransient(map[string][]byte{"traceCodeSecret": []byte("hmac-secret")})
+	hospitalContract := &chaincode.HospitalContract{}
+
+	traceCode, err := chaincode.GenerateTraceCode(ctx, "aspirin", "acme", 9.99, "2024-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("GenerateTraceCode returned error: %v", err)
+	}
+
+	decoded, err := hospitalContract.VerifyTraceCode(ctx, traceCode)
+	if err != nil {
+		t.Fatalf("VerifyTraceCode returned error for a signed trace code: %v", err)
+	}
+	if !decoded.Verified {
+		t.Fatalf("expected VerifyTraceCode's result to be Verified")
+	}
+}
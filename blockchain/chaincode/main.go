@@ -13,8 +13,9 @@ func main() {
 	manufacturerContract := new(chaincode.ManufacturerContract)
 	hospitalContract := new(chaincode.HospitalContract)
 	patientContract := new(chaincode.PatientContract)
+	supplyChainContract := new(chaincode.SupplyChainContract)
 
-	cc, err := contractapi.NewChaincode(manufacturerContract, hospitalContract, patientContract)
+	cc, err := contractapi.NewChaincode(manufacturerContract, hospitalContract, patientContract, supplyChainContract)
 	if err != nil {
 		log.Panicf("Error creating chaincode: %v", err)
 	}
@@ -0,0 +1,48 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"gateway/internal/api"
+	"gateway/internal/ledger"
+)
+
+func main() {
+	cfg := ledger.Config{
+		PeerEndpoint:   getenv("GATEWAY_PEER_ENDPOINT", "localhost:7051"),
+		PeerServerName: getenv("GATEWAY_PEER_SERVER_NAME", "peer0.org1.example.com"),
+		TLSCertPath:    os.Getenv("GATEWAY_PEER_TLS_CERT"),
+		MSPID:          os.Getenv("GATEWAY_MSP_ID"),
+		CertPath:       os.Getenv("GATEWAY_CLIENT_CERT"),
+		KeyPath:        os.Getenv("GATEWAY_CLIENT_KEY"),
+		ChannelName:    getenv("GATEWAY_CHANNEL", "healthcare"),
+		ChaincodeName:  getenv("GATEWAY_CHAINCODE", "healthcare-cc"),
+	}
+
+	ledgerClient, err := ledger.Connect(cfg)
+	if err != nil {
+		log.Fatalf("failed to connect to ledger: %v", err)
+	}
+	defer ledgerClient.Close()
+
+	server := api.NewServer(ledgerClient)
+
+	addr := getenv("GATEWAY_LISTEN_ADDR", ":8080")
+	log.Printf("fhir gateway listening on %s", addr)
+	if err := http.ListenAndServe(addr, server.Routes()); err != nil {
+		log.Fatalf("gateway server stopped: %v", err)
+	}
+}
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,96 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+)
+
+// graphqlHandler exposes a read-only GraphQL schema over the same ledger
+// calls the REST endpoints use, for clients that prefer to shape their own
+// query instead of hitting fixed FHIR routes.
+func (s *Server) graphqlHandler() http.Handler {
+	schema := s.buildGraphQLSchema()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		var request struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		result := graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  request.Query,
+			VariableValues: request.Variables,
+			Context:        r.Context(),
+		})
+
+		writeJSON(w, http.StatusOK, result)
+	})
+}
+
+func (s *Server) buildGraphQLSchema() graphql.Schema {
+	patientType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Patient",
+		Fields: graphql.Fields{
+			"name":      &graphql.Field{Type: graphql.String},
+			"birthDate": &graphql.Field{Type: graphql.String},
+			"height":    &graphql.Field{Type: graphql.Float},
+			"weight":    &graphql.Field{Type: graphql.Float},
+			"gender":    &graphql.Field{Type: graphql.String},
+			"contact":   &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"patient": &graphql.Field{
+				Type: patientType,
+				Args: graphql.FieldConfigArgument{
+					"name": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					name, _ := p.Args["name"].(string)
+
+					ledgerPatient, err := s.fetchLedgerPatient(p.Context, name)
+					if err != nil {
+						return nil, err
+					}
+
+					return map[string]interface{}{
+						"name":      ledgerPatient.Name,
+						"birthDate": ledgerPatient.BirthDate,
+						"height":    ledgerPatient.Height,
+						"weight":    ledgerPatient.Weight,
+						"gender":    ledgerPatient.Gender,
+						"contact":   ledgerPatient.Contact,
+					}, nil
+				},
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: query})
+	if err != nil {
+		// The schema above is a static literal; a failure here means the
+		// handler was built wrong and should fail loudly at startup.
+		panic(err)
+	}
+
+	return schema
+}
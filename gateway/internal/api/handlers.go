@@ -0,0 +1,361 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package api exposes the Fabric-Healthcare ledger as a FHIR R4
+// REST/GraphQL gateway, translating HTTP requests into Fabric Gateway SDK
+// chaincode invocations and their results back into FHIR resources.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gateway/internal/ledger"
+	"gateway/pkg/fhir"
+)
+
+// Server adapts incoming FHIR REST/GraphQL requests onto ledger calls.
+type Server struct {
+	ledger *ledger.Client
+}
+
+// NewServer returns a Server that calls out to the given ledger client.
+func NewServer(ledgerClient *ledger.Client) *Server {
+	return &Server{ledger: ledgerClient}
+}
+
+// Routes wires up the FHIR REST endpoints and the GraphQL endpoint.
+func (s *Server) Routes() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/fhir/Patient", s.handlePatient)
+	mux.HandleFunc("/fhir/Patient/", s.handlePatientMedicationHistory)
+	mux.HandleFunc("/fhir/Observation", s.handleObservation)
+	mux.HandleFunc("/fhir/MedicationStatement", s.handleMedicationStatement)
+	mux.Handle("/graphql", s.graphqlHandler())
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/fhir+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"resourceType": "OperationOutcome", "error": err.Error()})
+}
+
+// fetchLedgerPatient reads a patient's public record and merges in their
+// PII from GetPatientPII, so callers see the same LedgerPatient shape
+// CreatePatient accepts. GetPatientPII reads only
+// patientPrivateCollection, so it only succeeds on a peer holding that
+// collection (a copy SharePatientDataWith wrote into another org's
+// implicit collection is not read); every other peer's call fails here
+// instead of silently returning blank PII.
+func (s *Server) fetchLedgerPatient(ctx context.Context, name string) (fhir.LedgerPatient, error) {
+	publicResult, err := ledger.EvaluateJSON(ctx, s.ledger.Patient, "GetPatient", name)
+	if err != nil {
+		return fhir.LedgerPatient{}, err
+	}
+	var publicPatient struct {
+		Name string `json:"Name"`
+	}
+	if err := json.Unmarshal(publicResult, &publicPatient); err != nil {
+		return fhir.LedgerPatient{}, err
+	}
+
+	piiResult, err := ledger.EvaluateJSON(ctx, s.ledger.Patient, "GetPatientPII", name)
+	if err != nil {
+		return fhir.LedgerPatient{}, err
+	}
+	var ledgerPatient fhir.LedgerPatient
+	if err := json.Unmarshal(piiResult, &ledgerPatient); err != nil {
+		return fhir.LedgerPatient{}, err
+	}
+	ledgerPatient.Name = publicPatient.Name
+
+	return ledgerPatient, nil
+}
+
+// handlePatient implements GET /fhir/Patient?name=... and POST /fhir/Patient.
+func (s *Server) handlePatient(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	switch r.Method {
+	case http.MethodGet:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("name query parameter is required"))
+			return
+		}
+
+		ledgerPatient, err := s.fetchLedgerPatient(ctx, name)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, fhir.PatientFromLedger(ledgerPatient))
+
+	case http.MethodPost:
+		var patient fhir.Patient
+		if err := json.NewDecoder(r.Body).Decode(&patient); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		ledgerPatient, err := patient.ToLedger()
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		_, err = ledger.SubmitJSONWithTransient(ctx, s.ledger.Patient, "CreatePatient",
+			map[string][]byte{
+				"birthDate": []byte(ledgerPatient.BirthDate),
+				"height":    []byte(strconv.FormatFloat(ledgerPatient.Height, 'f', -1, 64)),
+				"weight":    []byte(strconv.FormatFloat(ledgerPatient.Weight, 'f', -1, 64)),
+				"gender":    []byte(ledgerPatient.Gender),
+				"contact":   []byte(ledgerPatient.Contact),
+			},
+			ledgerPatient.Name,
+		)
+		if err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, fhir.PatientFromLedger(ledgerPatient))
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleObservation implements GET /fhir/Observation?hospital=...&patient=...&reportId=...
+// and POST /fhir/Observation, which maps onto HospitalContract.ModifyReport.
+func (s *Server) handleObservation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	switch r.Method {
+	case http.MethodGet:
+		hospital := r.URL.Query().Get("hospital")
+		patient := r.URL.Query().Get("patient")
+		reportID := r.URL.Query().Get("reportId")
+		if hospital == "" || patient == "" || reportID == "" {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("hospital, patient, and reportId query parameters are required"))
+			return
+		}
+
+		result, err := ledger.EvaluateJSON(ctx, s.ledger.Hospital, "ViewReport", patient, hospital, reportID)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+
+		var report fhir.LedgerMedicalReport
+		if err := json.Unmarshal(result, &report); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, fhir.ObservationFromLedger(hospital, report))
+
+	case http.MethodPost:
+		var body struct {
+			Hospital    string   `json:"hospital"`
+			Patient     string   `json:"patient"`
+			Symptoms    string   `json:"symptoms"`
+			NeededDrugs []string `json:"neededDrugs"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		neededDrugsJSON, err := json.Marshal(body.NeededDrugs)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		result, err := ledger.SubmitJSONWithTransient(ctx, s.ledger.Hospital, "ModifyReport",
+			map[string][]byte{"symptoms": []byte(body.Symptoms)},
+			body.Hospital, body.Patient, string(neededDrugsJSON))
+		if err != nil {
+			writeError(w, http.StatusConflict, err)
+			return
+		}
+
+		report := fhir.LedgerMedicalReport{
+			PatientName: body.Patient,
+			Symptoms:    body.Symptoms,
+			NeededDrugs: body.NeededDrugs,
+		}
+		if id, err := strconv.Atoi(strings.TrimSpace(string(result))); err == nil {
+			report.ID = id
+		}
+
+		writeJSON(w, http.StatusCreated, fhir.ObservationFromLedger(body.Hospital, report))
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleMedicationStatement implements GET
+// /fhir/MedicationStatement?hospital=...&traceCode=...&patient=..., backed
+// by HospitalContract.QueryHospitalDrugsByField.
+func (s *Server) handleMedicationStatement(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	hospital := r.URL.Query().Get("hospital")
+	traceCode := r.URL.Query().Get("traceCode")
+	patient := r.URL.Query().Get("patient")
+	if hospital == "" || traceCode == "" || patient == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("hospital, traceCode, and patient query parameters are required"))
+		return
+	}
+
+	drug, err := s.findHospitalDrug(r.Context(), hospital, traceCode)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, fhir.MedicationStatementFromLedger(*drug, patient))
+}
+
+// findHospitalDrug looks up a single hospital inventory entry by trace
+// code via the CouchDB rich-query endpoint rather than Remove*, which
+// would mutate the ledger as a side effect.
+func (s *Server) findHospitalDrug(ctx context.Context, hospital, traceCode string) (*fhir.LedgerHospitalDrug, error) {
+	selector := fmt.Sprintf(`{"selector":{"HospitalName":%q,"TraceCode":%q}}`, hospital, traceCode)
+	result, err := ledger.EvaluateJSON(ctx, s.ledger.Hospital, "QueryHospitalDrugsByField", selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var drugs []fhir.LedgerHospitalDrug
+	if err := json.Unmarshal(result, &drugs); err != nil {
+		return nil, err
+	}
+	if len(drugs) == 0 {
+		return nil, fmt.Errorf("drug %s not found in %s inventory", traceCode, hospital)
+	}
+
+	return &drugs[0], nil
+}
+
+// handlePatientMedicationHistory implements
+// GET /fhir/Patient/{name}/$everything?hospital=..., composing a
+// patient's resource, their reports at hospital, and the drugs those
+// reports call for into a single FHIR Bundle.
+func (s *Server) handlePatientMedicationHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	name, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/fhir/Patient/"), "/$everything")
+	if !ok || name == "" {
+		writeError(w, http.StatusNotFound, fmt.Errorf("expected /fhir/Patient/{name}/$everything"))
+		return
+	}
+
+	hospital := r.URL.Query().Get("hospital")
+	if hospital == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("hospital query parameter is required"))
+		return
+	}
+
+	ctx := r.Context()
+
+	ledgerPatient, err := s.fetchLedgerPatient(ctx, name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	reportsResult, err := ledger.EvaluateJSON(ctx, s.ledger.Hospital, "GetReports", hospital)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	var reports []fhir.LedgerMedicalReport
+	if err := json.Unmarshal(reportsResult, &reports); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	var observations []*fhir.Observation
+	var statements []*fhir.MedicationStatement
+	var medications []*fhir.Medication
+
+	for _, report := range reports {
+		if report.PatientName != name {
+			continue
+		}
+		observations = append(observations, fhir.ObservationFromLedger(hospital, report))
+
+		for _, drugName := range report.NeededDrugs {
+			drug, err := s.findHospitalDrugByName(ctx, hospital, drugName)
+			if err != nil {
+				continue
+			}
+			statements = append(statements, fhir.MedicationStatementFromLedger(*drug, name))
+
+			manufacturerDrug, err := s.findManufacturerDrug(ctx, drug.TraceCode)
+			if err == nil {
+				medications = append(medications, fhir.MedicationFromLedger(*manufacturerDrug))
+			}
+		}
+	}
+
+	bundle := fhir.NewPatientMedicationHistory(fhir.PatientFromLedger(ledgerPatient), observations, statements, medications)
+	writeJSON(w, http.StatusOK, bundle)
+}
+
+func (s *Server) findHospitalDrugByName(ctx context.Context, hospital, drugName string) (*fhir.LedgerHospitalDrug, error) {
+	selector := fmt.Sprintf(`{"selector":{"HospitalName":%q,"Name":%q}}`, hospital, drugName)
+	result, err := ledger.EvaluateJSON(ctx, s.ledger.Hospital, "QueryHospitalDrugsByField", selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var drugs []fhir.LedgerHospitalDrug
+	if err := json.Unmarshal(result, &drugs); err != nil {
+		return nil, err
+	}
+	if len(drugs) == 0 {
+		return nil, fmt.Errorf("drug %s not found in %s inventory", drugName, hospital)
+	}
+
+	return &drugs[0], nil
+}
+
+func (s *Server) findManufacturerDrug(ctx context.Context, traceCode string) (*fhir.LedgerManufacturerDrug, error) {
+	selector := fmt.Sprintf(`{"selector":{"TraceCode":%q}}`, traceCode)
+	result, err := ledger.EvaluateJSON(ctx, s.ledger.Manufacturer, "QueryManufacturerDrugsByField", selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var drugs []fhir.LedgerManufacturerDrug
+	if err := json.Unmarshal(result, &drugs); err != nil {
+		return nil, err
+	}
+	if len(drugs) == 0 {
+		return nil, fmt.Errorf("manufacturer drug %s not found", traceCode)
+	}
+
+	return &drugs[0], nil
+}
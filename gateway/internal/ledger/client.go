@@ -0,0 +1,147 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package ledger wraps the Fabric Gateway SDK connection to the
+// Fabric-Healthcare chaincode, exposing a handle per contract and generic
+// evaluate/submit helpers that take the contract function's name.
+package ledger
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hyperledger/fabric-gateway/pkg/client"
+	"github.com/hyperledger/fabric-gateway/pkg/identity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Config holds the connection details needed to reach a peer's Gateway
+// service and sign transactions as a given identity.
+type Config struct {
+	PeerEndpoint   string
+	PeerServerName string
+	TLSCertPath    string
+	MSPID          string
+	CertPath       string
+	KeyPath        string
+	ChannelName    string
+	ChaincodeName  string
+}
+
+// Client is a thin, already-connected handle onto the patient, hospital,
+// and manufacturer Fabric-Healthcare contracts.
+type Client struct {
+	conn         *grpc.ClientConn
+	gateway      *client.Gateway
+	Patient      *client.Contract
+	Hospital     *client.Contract
+	Manufacturer *client.Contract
+}
+
+// Connect dials the peer's Gateway service, builds a signing identity from
+// the configured certificate and key, and returns handles onto the
+// patient, hospital, and manufacturer chaincode contracts.
+func Connect(cfg Config) (*Client, error) {
+	tlsCertPEM, err := os.ReadFile(cfg.TLSCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read peer TLS certificate: %w", err)
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(tlsCertPEM) {
+		return nil, fmt.Errorf("failed to parse peer TLS certificate")
+	}
+	transportCreds := credentials.NewClientTLSFromCert(certPool, cfg.PeerServerName)
+
+	conn, err := grpc.Dial(cfg.PeerEndpoint, grpc.WithTransportCredentials(transportCreds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial peer gateway: %w", err)
+	}
+
+	certPEM, err := os.ReadFile(cfg.CertPath)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read client certificate: %w", err)
+	}
+	cert, err := identity.CertificateFromPEM(certPEM)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+	}
+	id, err := identity.NewX509Identity(cfg.MSPID, cert)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to build client identity: %w", err)
+	}
+
+	keyPEM, err := os.ReadFile(cfg.KeyPath)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read client private key: %w", err)
+	}
+	privateKey, err := identity.PrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to parse client private key: %w", err)
+	}
+	sign, err := identity.NewPrivateKeySign(privateKey)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to build transaction signer: %w", err)
+	}
+
+	gateway, err := client.Connect(
+		id,
+		client.WithSign(sign),
+		client.WithClientConnection(conn),
+		client.WithEvaluateTimeout(5*time.Second),
+		client.WithEndorseTimeout(15*time.Second),
+		client.WithSubmitTimeout(5*time.Second),
+		client.WithCommitStatusTimeout(1*time.Minute),
+	)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect gateway: %w", err)
+	}
+
+	network := gateway.GetNetwork(cfg.ChannelName)
+
+	return &Client{
+		conn:         conn,
+		gateway:      gateway,
+		Patient:      network.GetContractWithName(cfg.ChaincodeName, "PatientContract"),
+		Hospital:     network.GetContractWithName(cfg.ChaincodeName, "HospitalContract"),
+		Manufacturer: network.GetContractWithName(cfg.ChaincodeName, "ManufacturerContract"),
+	}, nil
+}
+
+// Close tears down the Gateway connection.
+func (c *Client) Close() error {
+	c.gateway.Close()
+	return c.conn.Close()
+}
+
+// EvaluateJSON evaluates a read-only transaction and returns its raw JSON
+// result, using ctx only to cancel the call if the caller gives up early.
+func EvaluateJSON(ctx context.Context, contract *client.Contract, name string, args ...string) ([]byte, error) {
+	return contract.EvaluateWithContext(ctx, name, client.WithArguments(args...))
+}
+
+// SubmitJSON submits a state-changing transaction for ordering and commit,
+// returning its raw JSON result once committed.
+func SubmitJSON(ctx context.Context, contract *client.Contract, name string, args ...string) ([]byte, error) {
+	return contract.SubmitWithContext(ctx, name, client.WithArguments(args...))
+}
+
+// SubmitJSONWithTransient is SubmitJSON plus a transient map, for
+// transactions (CreatePatient, ModifyReport) that take PII/PHI
+// out-of-band instead of as a plain argument so it never lands in the
+// public proposal.
+func SubmitJSONWithTransient(ctx context.Context, contract *client.Contract, name string, transient map[string][]byte, args ...string) ([]byte, error) {
+	return contract.SubmitWithContext(ctx, name, client.WithArguments(args...), client.WithTransient(transient))
+}
@@ -0,0 +1,46 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fhir
+
+// Bundle is a FHIR searchset Bundle: a flat container for a mixed set of
+// resources, used here to return a patient's combined observations and
+// medications in a single response.
+type Bundle struct {
+	ResourceType string  `json:"resourceType"`
+	Type         string  `json:"type"`
+	Total        int     `json:"total"`
+	Entry        []Entry `json:"entry"`
+}
+
+// Entry is a single FHIR Bundle.entry, wrapping one resource of any type.
+type Entry struct {
+	Resource interface{} `json:"resource"`
+}
+
+// NewPatientMedicationHistory composes a patient's resource, their
+// reports, and the drugs traced back to them into a single FHIR Bundle,
+// mirroring how clinical systems return a combined
+// conditions+observations+medications document for a patient.
+func NewPatientMedicationHistory(patient *Patient, observations []*Observation, statements []*MedicationStatement, medications []*Medication) *Bundle {
+	entries := make([]Entry, 0, 1+len(observations)+len(statements)+len(medications))
+	entries = append(entries, Entry{Resource: patient})
+
+	for _, observation := range observations {
+		entries = append(entries, Entry{Resource: observation})
+	}
+	for _, statement := range statements {
+		entries = append(entries, Entry{Resource: statement})
+	}
+	for _, medication := range medications {
+		entries = append(entries, Entry{Resource: medication})
+	}
+
+	return &Bundle{
+		ResourceType: "Bundle",
+		Type:         "searchset",
+		Total:        len(entries),
+		Entry:        entries,
+	}
+}
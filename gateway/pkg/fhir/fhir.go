@@ -0,0 +1,46 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package fhir maps the ledger's Patient, MedicalReport, HospitalDrug, and
+// ManufacturerDrug records onto HL7 FHIR R4 resources, so the gateway can
+// speak FHIR to clinical systems without the chaincode itself knowing
+// anything about the standard.
+package fhir
+
+// Meta carries the minimal FHIR resource metadata every resource in this
+// package emits.
+type Meta struct {
+	VersionID   string `json:"versionId,omitempty"`
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+// Identifier is a FHIR Identifier datatype, used here to carry the
+// ledger-native name/trace code alongside the FHIR resource.
+type Identifier struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+// Coding is a single FHIR Coding datatype entry.
+type Coding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+// CodeableConcept is a FHIR CodeableConcept datatype: a coded value plus a
+// human readable fallback.
+type CodeableConcept struct {
+	Coding []Coding `json:"coding,omitempty"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// Reference is a FHIR Reference datatype, e.g. "Patient/alice".
+type Reference struct {
+	Reference string `json:"reference"`
+}
+
+// identifierSystem is the URI namespace every Identifier in this gateway
+// is minted under, since the ledger does not assign its own OIDs.
+const identifierSystem = "urn:fabric-healthcare:id"
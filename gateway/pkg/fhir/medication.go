@@ -0,0 +1,79 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fhir
+
+// Medication mirrors a ledger ManufacturerDrug in HL7 FHIR R4's
+// Medication resource shape.
+type Medication struct {
+	ResourceType string          `json:"resourceType"`
+	ID           string          `json:"id"`
+	Code         CodeableConcept `json:"code"`
+	Manufacturer Reference       `json:"manufacturer"`
+	Batch        MedicationBatch `json:"batch"`
+}
+
+// MedicationBatch is a FHIR Medication.batch backbone element, used here
+// to carry the trace code as the lot number.
+type MedicationBatch struct {
+	LotNumber string `json:"lotNumber"`
+}
+
+// MedicationStatement mirrors a ledger HospitalDrug in HL7 FHIR R4's
+// MedicationStatement resource shape: a drug in a hospital's inventory
+// that a patient has been or will be dispensed.
+type MedicationStatement struct {
+	ResourceType        string       `json:"resourceType"`
+	ID                  string       `json:"id"`
+	Status              string       `json:"status"`
+	MedicationReference Reference    `json:"medicationReference"`
+	Subject             Reference    `json:"subject"`
+	Note                []Annotation `json:"note,omitempty"`
+}
+
+// Annotation is a FHIR Annotation datatype.
+type Annotation struct {
+	Text string `json:"text"`
+}
+
+// LedgerManufacturerDrug is the JSON shape AddDrugToMnfcInventory uses.
+type LedgerManufacturerDrug struct {
+	Name           string  `json:"Name"`
+	TraceCode      string  `json:"TraceCode"`
+	Manufacturer   string  `json:"Manufacturer"`
+	Price          float64 `json:"Price"`
+	ProductionTime string  `json:"ProductionTime"`
+}
+
+// LedgerHospitalDrug is the JSON shape AddDrugToHospitalInventory uses.
+type LedgerHospitalDrug struct {
+	Name         string `json:"Name"`
+	TraceCode    string `json:"TraceCode"`
+	HospitalName string `json:"HospitalName"`
+}
+
+// MedicationFromLedger converts a ledger manufacturer drug into a FHIR
+// Medication resource.
+func MedicationFromLedger(drug LedgerManufacturerDrug) *Medication {
+	return &Medication{
+		ResourceType: "Medication",
+		ID:           drug.TraceCode,
+		Code:         CodeableConcept{Text: drug.Name},
+		Manufacturer: Reference{Reference: "Organization/" + drug.Manufacturer},
+		Batch:        MedicationBatch{LotNumber: drug.TraceCode},
+	}
+}
+
+// MedicationStatementFromLedger converts a ledger hospital inventory drug
+// into a FHIR MedicationStatement for the named patient.
+func MedicationStatementFromLedger(drug LedgerHospitalDrug, patientName string) *MedicationStatement {
+	return &MedicationStatement{
+		ResourceType:        "MedicationStatement",
+		ID:                  drug.TraceCode,
+		Status:              "active",
+		MedicationReference: Reference{Reference: "Medication/" + drug.TraceCode},
+		Subject:             Reference{Reference: "Patient/" + patientName},
+		Note:                []Annotation{{Text: "Dispensed by " + drug.HospitalName}},
+	}
+}
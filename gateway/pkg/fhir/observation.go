@@ -0,0 +1,41 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fhir
+
+import "strconv"
+
+// Observation mirrors a ledger MedicalReport's symptoms in HL7 FHIR R4's
+// Observation resource shape. NeededDrugs are surfaced separately as
+// MedicationStatements (see medication.go) rather than folded in here.
+type Observation struct {
+	ResourceType string          `json:"resourceType"`
+	ID           string          `json:"id"`
+	Status       string          `json:"status"`
+	Subject      Reference       `json:"subject"`
+	Code         CodeableConcept `json:"code"`
+	ValueString  string          `json:"valueString"`
+}
+
+// LedgerMedicalReport is the JSON shape ModifyReport/ViewReport use.
+type LedgerMedicalReport struct {
+	ID          int      `json:"ID"`
+	PatientName string   `json:"PatientName"`
+	Symptoms    string   `json:"Symptoms"`
+	NeededDrugs []string `json:"NeededDrugs"`
+}
+
+// ObservationFromLedger converts a ledger medical report into a FHIR
+// Observation resource scoped to hospitalName, since report IDs are only
+// unique within a single hospital.
+func ObservationFromLedger(hospitalName string, report LedgerMedicalReport) *Observation {
+	return &Observation{
+		ResourceType: "Observation",
+		ID:           hospitalName + "-" + strconv.Itoa(report.ID),
+		Status:       "final",
+		Subject:      Reference{Reference: "Patient/" + report.PatientName},
+		Code:         CodeableConcept{Text: "reported symptoms"},
+		ValueString:  report.Symptoms,
+	}
+}
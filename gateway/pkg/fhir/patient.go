@@ -0,0 +1,127 @@
+/*
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fhir
+
+import "fmt"
+
+// Patient mirrors a ledger patient (chaincode.Patient's name plus the
+// fields of chaincode.PatientPII) in HL7 FHIR R4's Patient resource shape.
+type Patient struct {
+	ResourceType string         `json:"resourceType"`
+	ID           string         `json:"id"`
+	Identifier   []Identifier   `json:"identifier"`
+	Name         []HumanName    `json:"name"`
+	Gender       string         `json:"gender"`
+	BirthDate    string         `json:"birthDate"`
+	Telecom      []ContactPoint `json:"telecom,omitempty"`
+	Extension    []Extension    `json:"extension,omitempty"`
+}
+
+// HumanName is a FHIR HumanName datatype. The ledger only stores a single
+// free-text name, so it is carried as Text rather than split into given/family.
+type HumanName struct {
+	Text string `json:"text"`
+}
+
+// ContactPoint is a FHIR ContactPoint datatype used for the patient's
+// contact information.
+type ContactPoint struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+// Extension carries ledger fields that have no first-class FHIR element,
+// such as height and weight, which FHIR instead models as Observations.
+type Extension struct {
+	URL          string   `json:"url"`
+	ValueDecimal *float64 `json:"valueDecimal,omitempty"`
+}
+
+// LedgerPatient is a patient's Name from GetPatient merged with the JSON
+// shape GetPatientPII returns from the chaincode (the same fields
+// CreatePatient takes via the transient map), decoupled from the chaincode
+// package so the gateway can be built and versioned independently of it.
+type LedgerPatient struct {
+	Name      string  `json:"Name"`
+	BirthDate string  `json:"BirthDate"`
+	Height    float64 `json:"Height"`
+	Weight    float64 `json:"Weight"`
+	Gender    string  `json:"Gender"`
+	Contact   string  `json:"Contact"`
+}
+
+// genderCode maps the ledger's free-text gender onto the FHIR
+// AdministrativeGender code system ("male", "female", "other", "unknown").
+func genderCode(ledgerGender string) string {
+	switch ledgerGender {
+	case "M", "Male", "male":
+		return "male"
+	case "F", "Female", "female":
+		return "female"
+	case "":
+		return "unknown"
+	default:
+		return "other"
+	}
+}
+
+// PatientFromLedger converts a ledger patient record into a FHIR Patient
+// resource.
+func PatientFromLedger(p LedgerPatient) *Patient {
+	height := p.Height
+	weight := p.Weight
+
+	return &Patient{
+		ResourceType: "Patient",
+		ID:           p.Name,
+		Identifier: []Identifier{
+			{System: identifierSystem, Value: p.Name},
+		},
+		Name:      []HumanName{{Text: p.Name}},
+		Gender:    genderCode(p.Gender),
+		BirthDate: p.BirthDate,
+		Telecom: []ContactPoint{
+			{System: "phone", Value: p.Contact},
+		},
+		Extension: []Extension{
+			{URL: identifierSystem + "/height-cm", ValueDecimal: &height},
+			{URL: identifierSystem + "/weight-kg", ValueDecimal: &weight},
+		},
+	}
+}
+
+// ToLedger converts a FHIR Patient resource back into the arguments
+// CreatePatient expects.
+func (p *Patient) ToLedger() (LedgerPatient, error) {
+	if len(p.Name) == 0 || p.Name[0].Text == "" {
+		return LedgerPatient{}, fmt.Errorf("fhir Patient.name is required")
+	}
+
+	ledger := LedgerPatient{
+		Name:      p.Name[0].Text,
+		BirthDate: p.BirthDate,
+		Gender:    p.Gender,
+	}
+
+	for _, telecom := range p.Telecom {
+		if telecom.System == "phone" {
+			ledger.Contact = telecom.Value
+		}
+	}
+
+	for _, ext := range p.Extension {
+		if ext.ValueDecimal == nil {
+			continue
+		}
+		switch ext.URL {
+		case identifierSystem + "/height-cm":
+			ledger.Height = *ext.ValueDecimal
+		case identifierSystem + "/weight-kg":
+			ledger.Weight = *ext.ValueDecimal
+		}
+	}
+
+	return ledger, nil
+}